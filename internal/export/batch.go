@@ -0,0 +1,155 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+
+	exportmodel "github.com/google/exposure-notifications-server/internal/export/model"
+	"github.com/google/exposure-notifications-server/internal/publish/model"
+)
+
+// BatchEncoder serializes the merged, sorted exposures for one batch into
+// the bytes that get written as that batch's export.bin. (The wire format
+// itself - the signed TemporaryExposureKeyExport proto - is unchanged by
+// this package; only how we get from Postgres rows to this call changes.)
+//
+// exposures is the live output of the k-way shard merge - the encoder must
+// range over it rather than buffering it into a slice itself, or the
+// streaming/sharding this package does upstream buys nothing; peak memory
+// for the whole Build call is only bounded if nothing downstream
+// materializes the full batch at once.
+type BatchEncoder interface {
+	Encode(ctx context.Context, exposures <-chan *model.Exposure, cfg *exportmodel.ExportConfig, sigInfos []*exportmodel.SignatureInfo) ([]byte, int, error)
+}
+
+// Batcher builds one ExportBatch at a time using a streaming,
+// keyset-paginated, sharded read path and a content-addressed write path.
+type Batcher struct {
+	Source  ExposureSource
+	Encoder BatchEncoder
+	Store   BlobStore
+	TmpDir  string // defaults to os.TempDir() if empty
+}
+
+// BuildResult describes the outcome of building a batch.
+type BuildResult struct {
+	SHA256Hex  string
+	ObjectName string
+	// Reused is true if an object with this SHA256 digest already existed
+	// in the bucket (dedup hit) and no new object was uploaded.
+	Reused   bool
+	RowCount int
+}
+
+// Build streams, shards, merges, encodes, and (content-addressed) uploads
+// one batch for cfg, using the given signature infos. Peak memory is
+// O(shardCount * pageSize), not O(total exposures in the window).
+func (b *Batcher) Build(ctx context.Context, cfg *exportmodel.ExportConfig, sigInfos []*exportmodel.SignatureInfo) (*BuildResult, error) {
+	tmpDir := b.TmpDir
+	if tmpDir == "" {
+		tmpDir = os.TempDir()
+	}
+
+	shardCount := cfg.ShardCountOrDefault()
+	pageSize := cfg.CursorPageSizeOrDefault()
+
+	results := make([]*shardResult, shardCount)
+	errs := make([]error, shardCount)
+	var wg sync.WaitGroup
+	for i := 0; i < shardCount; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := writeShard(ctx, b.Source, i, shardCount, pageSize, tmpDir)
+			results[i], errs[i] = r, err
+		}()
+	}
+	wg.Wait()
+
+	paths := make([]string, 0, shardCount)
+	defer func() {
+		for _, p := range paths {
+			os.Remove(p)
+		}
+	}()
+	for i, err := range errs {
+		if err != nil {
+			// Clean up whatever shards did succeed before bailing out.
+			for _, r := range results {
+				if r != nil {
+					os.Remove(r.path)
+				}
+			}
+			return nil, fmt.Errorf("export: shard %d failed: %w", i, err)
+		}
+		paths = append(paths, results[i].path)
+	}
+
+	merged, closeShards, err := mergeStream(paths)
+	if err != nil {
+		return nil, err
+	}
+	defer closeShards()
+
+	blob, rowCount, err := b.Encoder.Encode(ctx, merged, cfg, sigInfos)
+	// Encode may return before draining `merged` (e.g. on its own validation
+	// error). mergeStream's producer goroutine blocks on its next send until
+	// someone reads `merged` again, so drain whatever is left ourselves -
+	// otherwise that goroutine, and the shard file it's holding open, leaks
+	// for good.
+	for range merged {
+	}
+	if err != nil {
+		return nil, fmt.Errorf("export: encoding batch: %w", err)
+	}
+
+	sum := sha256.Sum256(blob)
+	digest := hex.EncodeToString(sum[:])
+
+	result := &BuildResult{SHA256Hex: digest, RowCount: rowCount}
+
+	if cfg.DedupEnabled {
+		exists, err := b.Store.Exists(ctx, digest)
+		if err != nil {
+			return nil, fmt.Errorf("export: checking for existing object %v: %w", digest, err)
+		}
+		if exists {
+			result.Reused = true
+			result.ObjectName = digest
+			if err := b.Store.AppendIndex(ctx, cfg.BucketName, cfg.FilenameRoot, result.ObjectName); err != nil {
+				return nil, fmt.Errorf("export: appending index for reused object: %w", err)
+			}
+			return result, nil
+		}
+	}
+
+	objectName, err := b.Store.Put(ctx, digest, blob)
+	if err != nil {
+		return nil, fmt.Errorf("export: uploading batch: %w", err)
+	}
+	result.ObjectName = objectName
+
+	if err := b.Store.AppendIndex(ctx, cfg.BucketName, cfg.FilenameRoot, objectName); err != nil {
+		return nil, fmt.Errorf("export: appending index: %w", err)
+	}
+	return result, nil
+}
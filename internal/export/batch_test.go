@@ -0,0 +1,206 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	exportmodel "github.com/google/exposure-notifications-server/internal/export/model"
+	"github.com/google/exposure-notifications-server/internal/publish/model"
+)
+
+// fakeSource partitions a fixed set of exposures across shards by index
+// modulo shardCount, matching the "every row returned by exactly one shard"
+// contract ExposureSource documents, and serves them back ordered and
+// paginated like a real keyset-paginated implementation would.
+type fakeSource struct {
+	all []*model.Exposure // must already be sorted by (IntervalNumber, ExposureKey)
+}
+
+func (s *fakeSource) Page(ctx context.Context, shard, shardCount int, after *Position, limit int) ([]*model.Exposure, error) {
+	var shardRows []*model.Exposure
+	for i, e := range s.all {
+		if i%shardCount == shard {
+			shardRows = append(shardRows, e)
+		}
+	}
+
+	start := 0
+	if after != nil {
+		start = len(shardRows)
+		for i, e := range shardRows {
+			if after.Less(positionOf(e)) {
+				start = i
+				break
+			}
+		}
+	}
+	if start >= len(shardRows) {
+		return nil, nil
+	}
+	end := start + limit
+	if end > len(shardRows) {
+		end = len(shardRows)
+	}
+	return shardRows[start:end], nil
+}
+
+// countingEncoder drains the merged channel (never buffering it itself) and
+// returns a deterministic blob plus the count of rows it saw, so tests can
+// assert both the row count and the ordering of what Build fed it.
+type countingEncoder struct {
+	seen []*model.Exposure
+}
+
+func (e *countingEncoder) Encode(ctx context.Context, exposures <-chan *model.Exposure, cfg *exportmodel.ExportConfig, sigInfos []*exportmodel.SignatureInfo) ([]byte, int, error) {
+	var buf bytes.Buffer
+	count := 0
+	for ex := range exposures {
+		e.seen = append(e.seen, ex)
+		fmt.Fprintf(&buf, "%x:%d;", ex.ExposureKey, ex.IntervalNumber)
+		count++
+	}
+	return buf.Bytes(), count, nil
+}
+
+// earlyReturnEncoder returns an error after reading only the first row,
+// like a real encoder rejecting a malformed exposure partway through the
+// batch - without itself draining the rest of the channel.
+type earlyReturnEncoder struct{}
+
+func (earlyReturnEncoder) Encode(ctx context.Context, exposures <-chan *model.Exposure, cfg *exportmodel.ExportConfig, sigInfos []*exportmodel.SignatureInfo) ([]byte, int, error) {
+	<-exposures
+	return nil, 0, fmt.Errorf("encoder: rejecting batch")
+}
+
+type fakeBlobStore struct {
+	existing map[string]bool
+	put      map[string][]byte
+	index    []string
+}
+
+func newFakeBlobStore() *fakeBlobStore {
+	return &fakeBlobStore{existing: map[string]bool{}, put: map[string][]byte{}}
+}
+
+func (s *fakeBlobStore) Exists(ctx context.Context, sha256Hex string) (bool, error) {
+	return s.existing[sha256Hex], nil
+}
+
+func (s *fakeBlobStore) Put(ctx context.Context, sha256Hex string, blob []byte) (string, error) {
+	s.put[sha256Hex] = blob
+	s.existing[sha256Hex] = true
+	return sha256Hex, nil
+}
+
+func (s *fakeBlobStore) AppendIndex(ctx context.Context, bucketName, filenameRoot, objectName string) error {
+	s.index = append(s.index, objectName)
+	return nil
+}
+
+func makeExposures(n int) []*model.Exposure {
+	exposures := make([]*model.Exposure, n)
+	for i := 0; i < n; i++ {
+		exposures[i] = &model.Exposure{
+			ExposureKey:    []byte{byte(i)},
+			IntervalNumber: int32(i),
+		}
+	}
+	return exposures
+}
+
+func TestBuildStreamsMergedShardsInOrder(t *testing.T) {
+	source := &fakeSource{all: makeExposures(20)}
+	encoder := &countingEncoder{}
+	store := newFakeBlobStore()
+
+	b := &Batcher{Source: source, Encoder: encoder, Store: store, TmpDir: t.TempDir()}
+	cfg := &exportmodel.ExportConfig{ShardCount: 4, CursorPageSize: 3, BucketName: "bucket", FilenameRoot: "root"}
+
+	result, err := b.Build(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if result.RowCount != 20 {
+		t.Errorf("RowCount = %v, want 20", result.RowCount)
+	}
+	if len(encoder.seen) != 20 {
+		t.Fatalf("encoder saw %v exposures, want 20", len(encoder.seen))
+	}
+	if !sort.SliceIsSorted(encoder.seen, func(i, j int) bool {
+		return positionOf(encoder.seen[i]).Less(positionOf(encoder.seen[j]))
+	}) {
+		t.Error("merged output is not globally ordered by (IntervalNumber, ExposureKey)")
+	}
+}
+
+func TestBuildDedupReusesExistingObject(t *testing.T) {
+	source := &fakeSource{all: makeExposures(5)}
+	store := newFakeBlobStore()
+
+	cfg := &exportmodel.ExportConfig{ShardCount: 2, CursorPageSize: 10, DedupEnabled: true, BucketName: "bucket", FilenameRoot: "root"}
+
+	b1 := &Batcher{Source: source, Encoder: &countingEncoder{}, Store: store, TmpDir: t.TempDir()}
+	first, err := b1.Build(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("first Build: %v", err)
+	}
+	if first.Reused {
+		t.Error("first build: Reused = true, want false (nothing uploaded yet)")
+	}
+
+	b2 := &Batcher{Source: source, Encoder: &countingEncoder{}, Store: store, TmpDir: t.TempDir()}
+	second, err := b2.Build(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatalf("second Build: %v", err)
+	}
+	if !second.Reused {
+		t.Error("second build: Reused = false, want true (identical content should dedup)")
+	}
+	if second.SHA256Hex != first.SHA256Hex {
+		t.Errorf("second build SHA256Hex = %v, want %v (same content)", second.SHA256Hex, first.SHA256Hex)
+	}
+	if len(store.index) != 2 {
+		t.Errorf("index has %v entries, want 2 (one per build, reused or not)", len(store.index))
+	}
+}
+
+// TestBuildDrainsMergeOnEncoderError guards against a leaked mergeStream
+// producer goroutine (and its open shard file) when Encode returns early
+// without consuming every row itself.
+func TestBuildDrainsMergeOnEncoderError(t *testing.T) {
+	source := &fakeSource{all: makeExposures(20)}
+	b := &Batcher{Source: source, Encoder: earlyReturnEncoder{}, Store: newFakeBlobStore(), TmpDir: t.TempDir()}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.Build(context.Background(), &exportmodel.ExportConfig{ShardCount: 4, CursorPageSize: 3}, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Build: want error from a rejecting encoder, got nil")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Build did not return - merge producer goroutine likely deadlocked on an undrained channel")
+	}
+}
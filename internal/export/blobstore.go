@@ -0,0 +1,35 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import "context"
+
+// BlobStore is the bucket-facing side of batch generation: it stores the
+// produced export.bin content, addressed by its SHA-256 digest, and
+// appends entries to the batch's index.txt.
+type BlobStore interface {
+	// Exists reports whether an object with this content digest has
+	// already been uploaded, so a re-export of the same TEK set can reuse
+	// it instead of writing a duplicate blob.
+	Exists(ctx context.Context, sha256Hex string) (bool, error)
+
+	// Put uploads blob under an object name derived from sha256Hex,
+	// returning that object's name.
+	Put(ctx context.Context, sha256Hex string, blob []byte) (objectName string, err error)
+
+	// AppendIndex appends objectName as a new line of the batch's
+	// index.txt.
+	AppendIndex(ctx context.Context, bucketName, filenameRoot string, objectName string) error
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/google/exposure-notifications-server/internal/publish/model"
+)
+
+// Position is a keyset pagination cursor: the (IntervalNumber, ExposureKey)
+// of the last row seen. Pages are fetched strictly after a Position rather
+// than with an OFFSET, so the query plan stays an index seek no matter how
+// deep into the export window the cursor is.
+type Position struct {
+	IntervalNumber int32
+	ExposureKey    []byte
+}
+
+// Less reports whether p sorts before o under the same ordering the
+// exposure source and shard merge use: (IntervalNumber, ExposureKey) ASC.
+func (p Position) Less(o Position) bool {
+	if p.IntervalNumber != o.IntervalNumber {
+		return p.IntervalNumber < o.IntervalNumber
+	}
+	return bytes.Compare(p.ExposureKey, o.ExposureKey) < 0
+}
+
+// ExposureSource streams the Exposure rows matching an ExportConfig's
+// region and time window, partitioned into `shardCount` disjoint shards.
+// Implementations (internal/export/database) partition by a stable hash of
+// ExposureKey so that every row is returned by exactly one shard, and each
+// shard's own rows come back ordered by (IntervalNumber, ExposureKey) -
+// which is what lets the k-way merge in merge.go reassemble a single
+// globally-ordered stream from the per-shard temp files.
+type ExposureSource interface {
+	// Page returns up to limit rows for the given shard, strictly after
+	// `after` (nil for the first page). An empty result means the shard is
+	// exhausted.
+	Page(ctx context.Context, shard, shardCount int, after *Position, limit int) ([]*model.Exposure, error)
+}
+
+func positionOf(e *model.Exposure) Position {
+	return Position{IntervalNumber: e.IntervalNumber, ExposureKey: e.ExposureKey}
+}
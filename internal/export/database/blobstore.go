@@ -0,0 +1,110 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+// GCSBlobStore implements export.BlobStore against a single GCS bucket,
+// addressing objects by their content's SHA-256 digest under
+// `blobs/<sha256>.bin` at the root of the bucket.
+type GCSBlobStore struct {
+	client *storage.Client
+	bucket string
+}
+
+// NewGCSBlobStore creates a GCSBlobStore for bucket.
+func NewGCSBlobStore(client *storage.Client, bucket string) *GCSBlobStore {
+	return &GCSBlobStore{client: client, bucket: bucket}
+}
+
+func blobObjectName(sha256Hex string) string {
+	return fmt.Sprintf("blobs/%s.bin", sha256Hex)
+}
+
+// Exists implements export.BlobStore.
+func (s *GCSBlobStore) Exists(ctx context.Context, sha256Hex string) (bool, error) {
+	_, err := s.client.Bucket(s.bucket).Object(blobObjectName(sha256Hex)).Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking for existing blob %v: %w", sha256Hex, err)
+	}
+	return true, nil
+}
+
+// Put implements export.BlobStore.
+func (s *GCSBlobStore) Put(ctx context.Context, sha256Hex string, blob []byte) (string, error) {
+	name := blobObjectName(sha256Hex)
+	w := s.client.Bucket(s.bucket).Object(name).NewWriter(ctx)
+	if _, err := w.Write(blob); err != nil {
+		w.Close()
+		return "", fmt.Errorf("writing blob %v: %w", sha256Hex, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("closing blob %v: %w", sha256Hex, err)
+	}
+	return name, nil
+}
+
+// AppendIndex implements export.BlobStore by appending objectName as a new
+// line to `<filenameRoot>/index.txt`, reading and rewriting it (GCS has no
+// native append). If objectName is already listed, the index is left
+// unchanged.
+func (s *GCSBlobStore) AppendIndex(ctx context.Context, bucketName, filenameRoot, objectName string) error {
+	indexName := fmt.Sprintf("%s/index.txt", filenameRoot)
+	obj := s.client.Bucket(bucketName).Object(indexName)
+
+	var lines []string
+	r, err := obj.NewReader(ctx)
+	switch err {
+	case nil:
+		defer r.Close()
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(r); err != nil {
+			return fmt.Errorf("reading existing index %v: %w", indexName, err)
+		}
+		for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	case storage.ErrObjectNotExist:
+		// First entry in a new index.
+	default:
+		return fmt.Errorf("reading existing index %v: %w", indexName, err)
+	}
+
+	for _, line := range lines {
+		if line == objectName {
+			return nil // already present, e.g. a retried dedup hit
+		}
+	}
+	lines = append(lines, objectName)
+
+	w := obj.NewWriter(ctx)
+	if _, err := w.Write([]byte(strings.Join(lines, "\n") + "\n")); err != nil {
+		w.Close()
+		return fmt.Errorf("writing index %v: %w", indexName, err)
+	}
+	return w.Close()
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package database persists export.model.ExportConfig/SignatureInfo rows
+// and serves internal/export.ExposureSource's keyset-paginated, sharded
+// reads over the `exposure` table.
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coredb "github.com/google/exposure-notifications-server/internal/database"
+	"github.com/google/exposure-notifications-server/internal/export"
+	"github.com/google/exposure-notifications-server/internal/export/model"
+	publishmodel "github.com/google/exposure-notifications-server/internal/publish/model"
+)
+
+// ExportDB provides access to export configuration and the exposures a
+// batch is built from.
+type ExportDB struct {
+	db *coredb.DB
+}
+
+// New creates an ExportDB.
+func New(db *coredb.DB) *ExportDB {
+	return &ExportDB{db: db}
+}
+
+// AddSignatureInfo inserts si, populating si.ID.
+func (d *ExportDB) AddSignatureInfo(ctx context.Context, si *model.SignatureInfo) error {
+	row := d.db.Pool.QueryRow(ctx, `
+		INSERT INTO signature_info (signing_key, signing_key_version, signing_key_id)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, si.SigningKey, si.SigningKeyVersion, si.SigningKeyID)
+	if err := row.Scan(&si.ID); err != nil {
+		return fmt.Errorf("inserting signature_info: %w", err)
+	}
+	return nil
+}
+
+// AddExportConfig inserts ec, populating ec.ConfigID.
+func (d *ExportDB) AddExportConfig(ctx context.Context, ec *model.ExportConfig) error {
+	row := d.db.Pool.QueryRow(ctx, `
+		INSERT INTO export_config
+			(bucket_name, filename_root, period_seconds, region, from_timestamp,
+			 thru_timestamp, signature_info_ids, shard_count, cursor_page_size, dedup_enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING config_id
+	`, ec.BucketName, ec.FilenameRoot, int64(ec.Period.Seconds()), ec.Region, ec.From,
+		ec.Thru, ec.SignatureInfoIDs, ec.ShardCount, ec.CursorPageSize, ec.DedupEnabled)
+	if err := row.Scan(&ec.ConfigID); err != nil {
+		return fmt.Errorf("inserting export_config: %w", err)
+	}
+	return nil
+}
+
+// exposureSource implements export.ExposureSource for one export window.
+type exposureSource struct {
+	db         *coredb.DB
+	region     string
+	from, thru time.Time
+}
+
+// NewExposureSource returns an export.ExposureSource over the exposures
+// that match cfg's region and [From, Thru) window.
+func NewExposureSource(db *coredb.DB, cfg *model.ExportConfig) export.ExposureSource {
+	return &exposureSource{db: db, region: cfg.Region, from: cfg.From, thru: cfg.Thru}
+}
+
+// Page implements export.ExposureSource. Shards partition rows by
+// `mod(abs(hashtext(encode(exposure_key, 'hex'))), shardCount)` so that every row is returned
+// by exactly one shard, and pagination within a shard uses a
+// (interval_number, exposure_key) keyset rather than OFFSET so the query
+// stays an index seek no matter how deep the cursor is.
+func (s *exposureSource) Page(ctx context.Context, shard, shardCount int, after *export.Position, limit int) ([]*publishmodel.Exposure, error) {
+	afterInterval, afterKey := int32(0), []byte{}
+	if after != nil {
+		afterInterval, afterKey = after.IntervalNumber, after.ExposureKey
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT exposure_key, transmission_risk, app_package_name, regions,
+		       interval_number, interval_count, created_at, local_provenance, sync_id
+		FROM exposure
+		WHERE regions @> ARRAY[$1]
+		  AND created_at >= $2 AND created_at < $3
+		  AND mod(abs(hashtext(encode(exposure_key, 'hex'))), $4) = $5
+		  AND (interval_number, exposure_key) > ($6, $7)
+		ORDER BY interval_number, exposure_key
+		LIMIT $8
+	`, s.region, s.from, s.thru, shardCount, shard, afterInterval, afterKey, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying exposures: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*publishmodel.Exposure
+	for rows.Next() {
+		var e publishmodel.Exposure
+		if err := rows.Scan(&e.ExposureKey, &e.TransmissionRisk, &e.AppPackageName, &e.Regions,
+			&e.IntervalNumber, &e.IntervalCount, &e.CreatedAt, &e.LocalProvenance, &e.FederationSyncID); err != nil {
+			return nil, fmt.Errorf("scanning exposure row: %w", err)
+		}
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,22 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export builds export batches without holding an entire region's
+// exposures in memory. It streams rows out of Postgres with a
+// keyset-paginated cursor, shards batch generation across N workers that
+// each write a locally-sorted temp file, k-way merges those shards back
+// into one globally-sorted stream, and content-addresses the resulting
+// blob by its SHA-256 digest so a re-exported window can reuse an
+// already-uploaded object instead of writing a duplicate.
+package export
@@ -0,0 +1,116 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/exposure-notifications-server/internal/publish/model"
+)
+
+// mergeStream k-way merges the shard files at `paths` (each individually
+// sorted by (IntervalNumber, ExposureKey), per writeShard) into a single
+// stream in the same order, without reading more than one record per shard
+// into memory at a time - so peak memory is O(shardCount), not O(total
+// exposures).
+//
+// The returned function must be called to release the open shard files
+// once the caller is done draining the channel (including on error).
+func mergeStream(paths []string) (<-chan *model.Exposure, func(), error) {
+	q := &mergeQueue{}
+	closeAll := func() {
+		for _, item := range *q {
+			item.file.Close()
+		}
+	}
+
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("export: opening shard file %v: %w", p, err)
+		}
+		item := &mergeItem{file: f, dec: gob.NewDecoder(f)}
+		if err := item.advance(); err != nil && err != io.EOF {
+			closeAll()
+			return nil, nil, fmt.Errorf("export: reading shard file %v: %w", p, err)
+		} else if err == nil {
+			heap.Push(q, item)
+		}
+	}
+
+	out := make(chan *model.Exposure)
+	go func() {
+		defer close(out)
+		for q.Len() > 0 {
+			item := heap.Pop(q).(*mergeItem)
+			out <- item.current
+			if err := item.advance(); err == nil {
+				heap.Push(q, item)
+			}
+		}
+	}()
+
+	return out, closeAll, nil
+}
+
+// mergeItem is one shard file's read position in the k-way merge.
+type mergeItem struct {
+	file    *os.File
+	dec     *gob.Decoder
+	current *model.Exposure
+}
+
+// advance reads the next record from this shard into `current`. Returns
+// io.EOF when the shard is exhausted.
+func (m *mergeItem) advance() error {
+	var e model.Exposure
+	if err := m.dec.Decode(&e); err != nil {
+		if err == io.EOF {
+			m.file.Close()
+		}
+		return err
+	}
+	m.current = &e
+	return nil
+}
+
+// mergeQueue is a container/heap.Interface min-heap of mergeItem ordered by
+// (IntervalNumber, ExposureKey), matching the order writeShard wrote.
+type mergeQueue []*mergeItem
+
+func (q mergeQueue) Len() int { return len(q) }
+
+func (q mergeQueue) Less(i, j int) bool {
+	return positionOf(q[i].current).Less(positionOf(q[j].current))
+}
+
+func (q mergeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *mergeQueue) Push(x interface{}) {
+	*q = append(*q, x.(*mergeItem))
+}
+
+func (q *mergeQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package model holds the database-backed configuration for export batch
+// generation: which region/bucket/signing key a batch is produced for, and
+// how the batcher in internal/export should produce it.
+package model
+
+import "time"
+
+// defaultShardCount and defaultCursorPageSize are what ShardCountOrDefault
+// and CursorPageSizeOrDefault return, and so what the batcher in
+// internal/export uses, when an ExportConfig doesn't set them.
+const (
+	defaultShardCount     = 1
+	defaultCursorPageSize = 1000
+)
+
+// ExportConfig describes a periodic export job for a single region. The
+// export batcher (internal/export) reads one of these per run and produces
+// ExportBatch rows / export files for [From, Thru) on a `Period` cadence.
+type ExportConfig struct {
+	ConfigID         int64         `db:"config_id"`
+	BucketName       string        `db:"bucket_name"`
+	FilenameRoot     string        `db:"filename_root"`
+	Period           time.Duration `db:"period_seconds"`
+	Region           string        `db:"region"`
+	From             time.Time     `db:"from_timestamp"`
+	Thru             time.Time     `db:"thru_timestamp"`
+	SignatureInfoIDs []int64       `db:"signature_info_ids"`
+
+	// ShardCount is the number of workers that generate this export's
+	// batches in parallel, each streaming a disjoint, keyset-paginated
+	// slice of exposures to its own temp file; the batcher then k-way
+	// merges the shards back into sorted order. <= 0 means
+	// defaultShardCount (no sharding).
+	ShardCount int `db:"shard_count"`
+
+	// CursorPageSize is how many exposure rows the batcher fetches per
+	// keyset-paginated page while streaming out of Postgres. <= 0 means
+	// defaultCursorPageSize.
+	CursorPageSize int `db:"cursor_page_size"`
+
+	// DedupEnabled turns on content-addressable dedup: a produced batch
+	// blob is only uploaded if an object with the same SHA-256 digest
+	// doesn't already exist in the bucket, and `index.txt` is simply
+	// appended to point at the existing object.
+	DedupEnabled bool `db:"dedup_enabled"`
+}
+
+// ShardCountOrDefault returns c.ShardCount, or defaultShardCount if unset.
+func (c *ExportConfig) ShardCountOrDefault() int {
+	if c.ShardCount <= 0 {
+		return defaultShardCount
+	}
+	return c.ShardCount
+}
+
+// CursorPageSizeOrDefault returns c.CursorPageSize, or
+// defaultCursorPageSize if unset.
+func (c *ExportConfig) CursorPageSizeOrDefault() int {
+	if c.CursorPageSize <= 0 {
+		return defaultCursorPageSize
+	}
+	return c.CursorPageSize
+}
+
+// SignatureInfo represents a signing key that a batch's signature
+// infos can reference. Multiple SignatureInfo can be attached to a single
+// ExportConfig (e.g. during a key rotation, old and new keys both sign).
+type SignatureInfo struct {
+	ID                int64  `db:"id"`
+	SigningKey        string `db:"signing_key"`
+	SigningKeyVersion string `db:"signing_key_version"`
+	SigningKeyID      string `db:"signing_key_id"`
+}
+
+// ExportBatch is a single produced run of an ExportConfig, covering
+// [StartTimestamp, EndTimestamp).
+type ExportBatch struct {
+	BatchID        int64     `db:"batch_id"`
+	ConfigID       int64     `db:"config_id"`
+	BucketName     string    `db:"bucket_name"`
+	FilenameRoot   string    `db:"filename_root"`
+	StartTimestamp time.Time `db:"start_timestamp"`
+	EndTimestamp   time.Time `db:"end_timestamp"`
+	Region         string    `db:"region"`
+	Status         string    `db:"status"`
+}
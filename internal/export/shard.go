@@ -0,0 +1,72 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// shardResult is the outcome of streaming one shard's exposures to a temp
+// file on disk.
+type shardResult struct {
+	path  string
+	count int
+}
+
+// writeShard streams every exposure belonging to `shard` (of `shardCount`)
+// to a new temp file in tmpDir, one page at a time, so this worker never
+// holds more than `pageSize` exposures in memory. The file holds the rows
+// in the same (IntervalNumber, ExposureKey) order the source returned them
+// in, which merge.go relies on.
+func writeShard(ctx context.Context, source ExposureSource, shard, shardCount, pageSize int, tmpDir string) (*shardResult, error) {
+	f, err := ioutil.TempFile(tmpDir, fmt.Sprintf("export-shard-%d-*.gob", shard))
+	if err != nil {
+		return nil, fmt.Errorf("export: creating shard %d temp file: %w", shard, err)
+	}
+	defer f.Close()
+
+	enc := gob.NewEncoder(f)
+	var after *Position
+	count := 0
+
+	for {
+		page, err := source.Page(ctx, shard, shardCount, after, pageSize)
+		if err != nil {
+			os.Remove(f.Name())
+			return nil, fmt.Errorf("export: reading shard %d page: %w", shard, err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, e := range page {
+			if err := enc.Encode(e); err != nil {
+				os.Remove(f.Name())
+				return nil, fmt.Errorf("export: writing shard %d: %w", shard, err)
+			}
+			count++
+		}
+		pos := positionOf(page[len(page)-1])
+		after = &pos
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	return &shardResult{path: f.Name(), count: count}, nil
+}
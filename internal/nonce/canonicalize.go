@@ -0,0 +1,130 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nonce
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NonceVersion selects which canonicalization rules Canonicalize applies.
+type NonceVersion int
+
+const (
+	// NonceV1 matches the original AndroidNonce behavior: sorted
+	// base64(key).intervalNumber.intervalCount.transmissionRisk tuples,
+	// uppercased+sorted regions, and the verification payload, pipe
+	// joined.
+	NonceV1 NonceVersion = iota
+
+	// NonceV2 additionally folds in a bucketed Padding length and the
+	// v1.5+ per-key ReportType/DaysSinceOnset fields, so that attestation
+	// commits to them too. Clients on the v1.5+ schema use this; clients
+	// that predate those fields keep using NonceV1.
+	NonceV2
+)
+
+// paddingBucketSize is the granularity NonceV2 buckets Padding length
+// into, so that attestation commits to roughly how much padding was sent
+// without making the nonce sensitive to its exact byte count (padding
+// length is deliberately randomized per request by well-behaved clients).
+const paddingBucketSize = 256
+
+// Key is the subset of an exposure key's fields that feed into the nonce.
+type Key struct {
+	Key              string
+	IntervalNumber   int32
+	IntervalCount    int32
+	TransmissionRisk int
+
+	// ReportType and DaysSinceOnset are part of the v1.5+ schema and are
+	// only folded into NonceV2 and later.
+	ReportType     string
+	DaysSinceOnset int32
+}
+
+// Request is the subset of a publish request's fields that feed into the
+// nonce, independent of the request's own wire representation.
+type Request struct {
+	AppPackageName      string
+	Keys                []Key
+	Regions             []string
+	VerificationPayload string
+	Padding             string
+}
+
+// Canonicalize builds the canonical cleartext for req under NonceVersion v.
+// The caller is expected to sha256 + base64 the result (as
+// model.Publish.AndroidNonce/IOSNonce do) - Canonicalize itself returns the
+// pre-hash cleartext so version-specific logic and hashing stay decoupled.
+func Canonicalize(req *Request, v NonceVersion) ([]byte, error) {
+	switch v {
+	case NonceV1:
+		return canonicalizeV1(req), nil
+	case NonceV2:
+		return canonicalizeV2(req), nil
+	default:
+		return nil, fmt.Errorf("nonce: unknown NonceVersion %v", v)
+	}
+}
+
+func sortedKeys(keys []Key) []Key {
+	sorted := make([]Key, len(keys))
+	copy(sorted, keys)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	return sorted
+}
+
+func sortedRegions(regions []string) []string {
+	sorted := make([]string, len(regions))
+	for i, r := range regions {
+		sorted[i] = strings.ToUpper(r)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func canonicalizeV1(req *Request) []byte {
+	keys := make([]string, 0, len(req.Keys))
+	for _, k := range sortedKeys(req.Keys) {
+		keys = append(keys, fmt.Sprintf("%v.%v.%v.%v", k.Key, k.IntervalNumber, k.IntervalCount, k.TransmissionRisk))
+	}
+
+	cleartext := req.AppPackageName + "|" +
+		strings.Join(keys, ",") + "|" +
+		strings.Join(sortedRegions(req.Regions), ",") + "|" +
+		req.VerificationPayload
+
+	return []byte(cleartext)
+}
+
+func canonicalizeV2(req *Request) []byte {
+	keys := make([]string, 0, len(req.Keys))
+	for _, k := range sortedKeys(req.Keys) {
+		keys = append(keys, fmt.Sprintf("%v.%v.%v.%v.%v.%v",
+			k.Key, k.IntervalNumber, k.IntervalCount, k.TransmissionRisk, k.ReportType, k.DaysSinceOnset))
+	}
+
+	paddingBucket := len(req.Padding) / paddingBucketSize
+
+	cleartext := req.AppPackageName + "|" +
+		strings.Join(keys, ",") + "|" +
+		strings.Join(sortedRegions(req.Regions), ",") + "|" +
+		req.VerificationPayload + "|" +
+		fmt.Sprintf("%v", paddingBucket)
+
+	return []byte(cleartext)
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nonce is the versioned canonicalization used to build the nonce
+// that device attestation (Android SafetyNet, iOS App Attest) commits to.
+// Client and server must derive byte-for-byte identical cleartext from the
+// same publish request, so the rules here are deliberately frozen per
+// NonceVersion rather than evolving in place - a new client/API schema
+// adds a new NonceVersion instead of changing an existing one.
+//
+// This package does not depend on internal/publish/model (that package
+// depends on this one for model.Publish.AndroidNonce/IOSNonce), so callers
+// convert their request into a Request before calling Canonicalize.
+package nonce
@@ -15,9 +15,11 @@
 package model
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"log"
 	"os"
 	"sort"
 	"strconv"
@@ -25,6 +27,9 @@ import (
 	"time"
 
 	"github.com/google/exposure-notifications-server/internal/base64util"
+	"github.com/google/exposure-notifications-server/internal/nonce"
+	"github.com/google/exposure-notifications-server/internal/verification"
+	"github.com/google/exposure-notifications-server/internal/verification/external"
 )
 
 const (
@@ -60,9 +65,11 @@ const (
 //  the transmission risk for this publish.
 // Verification: The attestation payload for this request. (iOS or Android specific)
 //   Base64 encoded.
-// VerificationAuthorityName: a string that should be verified against the code provider.
-//  Note: This project doesn't directly include a diagnosis code verification System
-//        but does provide the ability to configure one in `serverevn.ServerEnv`
+// VerificationPayload: a JWT issued by a health authority's diagnosis
+//  verification server once a diagnosis code has been confirmed. When a
+//  `verification.Verifier` is configured on the Transformer (see
+//  WithVerifier), this is validated the way an OIDC relying party validates
+//  an ID token, and must commit to the exact TEK set being published.
 type Publish struct {
 	Keys                      []ExposureKey `json:"temporaryExposureKeys"`
 	Regions                   []string      `json:"regions"`
@@ -73,47 +80,57 @@ type Publish struct {
 	Padding                   string        `json:"padding"`
 }
 
-// AndroidNonce returns the Android. This ensures that the data in the request
-// is the same data that was used to create the device attestation.
-func (p *Publish) AndroidNonce() string {
-	// base64 keys are to be lexicographically sorted
-	sortedKeys := make([]ExposureKey, len(p.Keys))
-	copy(sortedKeys, p.Keys)
-	sort.Slice(sortedKeys, func(i int, j int) bool {
-		return sortedKeys[i].Key < sortedKeys[j].Key
-	})
-
-	// regions are to be uppercased and then lexographically sorted
-	sortedRegions := make([]string, len(p.Regions))
-	for i, r := range p.Regions {
-		sortedRegions[i] = strings.ToUpper(r)
+// toNonceRequest converts this Publish to the form the nonce package
+// canonicalizes, so that package doesn't need to depend on this one (which
+// depends on it for AndroidNonce/IOSNonce).
+func (p *Publish) toNonceRequest() *nonce.Request {
+	keys := make([]nonce.Key, len(p.Keys))
+	for i, k := range p.Keys {
+		keys[i] = nonce.Key{
+			Key:              k.Key,
+			IntervalNumber:   k.IntervalNumber,
+			IntervalCount:    k.IntervalCount,
+			TransmissionRisk: k.TransmissionRisk,
+			ReportType:       k.ReportType,
+			DaysSinceOnset:   k.DaysSinceOnsetOfSymptoms,
+		}
+	}
+	return &nonce.Request{
+		AppPackageName:      p.AppPackageName,
+		Keys:                keys,
+		Regions:             p.Regions,
+		VerificationPayload: p.VerificationPayload,
+		Padding:             p.Padding,
 	}
-	sort.Strings(sortedRegions)
+}
 
-	keys := make([]string, 0, len(sortedKeys))
-	for _, k := range sortedKeys {
-		keys = append(keys, fmt.Sprintf("%v.%v.%v.%v", k.Key, k.IntervalNumber, k.IntervalCount, k.TransmissionRisk))
+// AndroidNonce returns the nonce Android clients are expected to embed in
+// their SafetyNet attestation. This ensures that the data in the request is
+// the same data that was used to create the device attestation.
+func (p *Publish) AndroidNonce() string {
+	cleartext, err := nonce.Canonicalize(p.toNonceRequest(), nonce.NonceV1)
+	if err != nil {
+		// NonceV1 is a known-valid NonceVersion, so Canonicalize cannot fail.
+		panic(fmt.Sprintf("nonce: %v", err))
 	}
+	sum := sha256.Sum256(cleartext)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
 
-	// The cleartext is a combination of all of the data on the request
-	// in a specific order.
-	//
-	// appPackageName|key[,key]|region[,region]|verificationAuthorityName
-	// Keys are encoded as
-	//     base64(exposureKey).intervalNumber.IntervalCount.transmissionRisk
-	// When there is > 1 key, keys are comma separated.
-	// Keys must in sorted order based on the sorting of the base64 exposure key.
-	// Regions are uppercased, sorted, and comma separated
-	cleartext :=
-		p.AppPackageName + "|" +
-			strings.Join(keys, ",") + "|" + // where key is b64key.intervalNum.intervalCount
-			strings.Join(sortedRegions, ",") + "|" +
-			p.VerificationPayload
-
-	// Take the sha256 checksum of that data
-	sum := sha256.Sum256([]byte(cleartext))
-
-	// Base64 encode the result.
+// IOSNonce returns the nonce iOS clients are expected to embed in their App
+// Attest assertion's clientDataHash, using the same canonicalization rules
+// as AndroidNonce (sorted keys, uppercased+sorted regions). Unlike
+// AndroidNonce this is not itself sent to the server - the client hashes it
+// again as SHA256(IOSNonce()) to form clientDataHash, and the server
+// recomputes and compares the same value when validating the assertion. See
+// `verification/ios`.
+func (p *Publish) IOSNonce() string {
+	cleartext, err := nonce.Canonicalize(p.toNonceRequest(), nonce.NonceV1)
+	if err != nil {
+		// NonceV1 is a known-valid NonceVersion, so Canonicalize cannot fail.
+		panic(fmt.Sprintf("nonce: %v", err))
+	}
+	sum := sha256.Sum256(cleartext)
 	return base64.StdEncoding.EncodeToString(sum[:])
 }
 
@@ -135,6 +152,13 @@ type ExposureKey struct {
 	IntervalNumber   int32  `json:"rollingStartNumber"`
 	IntervalCount    int32  `json:"rollingPeriod"`
 	TransmissionRisk int    `json:"transmissionRisk"`
+
+	// ReportType and DaysSinceOnsetOfSymptoms are part of the v1.5+ API
+	// schema. They are optional; clients on the v1 schema leave them zero
+	// valued, and NonceV1 (the only version currently wired up) does not
+	// commit to them - see NonceV2 in the nonce package.
+	ReportType               string `json:"reportType,omitempty"`
+	DaysSinceOnsetOfSymptoms int32  `json:"daysSinceOnsetOfSymptoms,omitempty"`
 }
 
 // ExposureKeys represents a set of ExposureKey objects as input to
@@ -178,20 +202,65 @@ type Transformer struct {
 	maxExposureKeys     int
 	maxIntervalStartAge time.Duration // How many intervals old does this server accept?
 	truncateWindow      time.Duration
+
+	verifier             *verification.Verifier
+	attestationVerifiers map[string]verification.AttestationVerifier
+	externalVerifier     external.Verifier
+}
+
+// TransformerOption configures optional behavior of a Transformer. See
+// WithVerifier, WithAttestationVerifiers and WithExternalVerifiers.
+type TransformerOption func(*Transformer)
+
+// WithVerifier attaches a diagnosis verification.Verifier to the
+// Transformer. When set, TransformPublish validates
+// `Publish.VerificationPayload` against it before admitting the keys. The
+// verifier's own Policy (fail-closed vs shadow) decides whether a
+// verification failure rejects the publish.
+func WithVerifier(v *verification.Verifier) TransformerOption {
+	return func(t *Transformer) {
+		t.verifier = v
+	}
+}
+
+// WithAttestationVerifiers attaches device attestation verifiers to the
+// Transformer, keyed by the `Publish.Platform` value they handle (e.g.
+// "android", "ios"). When a Publish arrives for a platform with a
+// registered verifier, TransformPublish validates
+// `Publish.DeviceVerificationPayload` against it and rejects the publish on
+// failure.
+func WithAttestationVerifiers(byPlatform map[string]verification.AttestationVerifier) TransformerOption {
+	return func(t *Transformer) {
+		t.attestationVerifiers = byPlatform
+	}
+}
+
+// WithExternalVerifiers attaches an out-of-process external.Verifier (see
+// external.NewPool) to the Transformer. When set, TransformPublish calls it
+// after all local validation succeeds, and applies its decision (reject,
+// delay, or accept with transmission risk / region overrides).
+func WithExternalVerifiers(v external.Verifier) TransformerOption {
+	return func(t *Transformer) {
+		t.externalVerifier = v
+	}
 }
 
 // NewTransformer creates a transformer for turning publish API requests into
 // records for insertion into the database. On the call to TransformPublish
 // all data is validated according to the transformer that is used.
-func NewTransformer(maxExposureKeys int, maxIntervalStartAge time.Duration, truncateWindow time.Duration) (*Transformer, error) {
+func NewTransformer(maxExposureKeys int, maxIntervalStartAge time.Duration, truncateWindow time.Duration, opts ...TransformerOption) (*Transformer, error) {
 	if maxExposureKeys < 0 || maxExposureKeys > maxKeysPerPublish {
 		return nil, fmt.Errorf("maxExposureKeys must be > 0 and <= %v, got %v", maxKeysPerPublish, maxExposureKeys)
 	}
-	return &Transformer{
+	t := &Transformer{
 		maxExposureKeys:     maxExposureKeys,
 		maxIntervalStartAge: maxIntervalStartAge,
 		truncateWindow:      truncateWindow,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t, nil
 }
 
 // TransformExposureKey converts individual key data to an exposure entity.
@@ -246,13 +315,32 @@ func TransformExposureKey(exposureKey ExposureKey, appPackageName string, upcase
 	}, nil
 }
 
+// toTEKs converts this request's keys to the form the verification and
+// external packages operate on, so they don't need to depend on this
+// package (which depends on them to configure a Transformer).
+func toTEKs(keys []ExposureKey) []verification.TEK {
+	teks := make([]verification.TEK, len(keys))
+	for i, k := range keys {
+		teks[i] = verification.TEK{
+			Key:              k.Key,
+			IntervalNumber:   k.IntervalNumber,
+			IntervalCount:    k.IntervalCount,
+			TransmissionRisk: k.TransmissionRisk,
+		}
+	}
+	return teks
+}
+
 // TransformPublish converts incoming key data to a list of exposure entities.
 // The data in the request is validated during the transform, including:
 //
 // * 0 exposure Keys in the requests
 // * > Transformer.maxExposureKeys in the request
+// * diagnosis verification, if a verification.Verifier is configured
+// * device attestation, if an AttestationVerifier is configured for the platform
+// * out-of-process verification, if an external.Verifier is configured
 //
-func (t *Transformer) TransformPublish(inData *Publish, batchTime time.Time) ([]*Exposure, error) {
+func (t *Transformer) TransformPublish(ctx context.Context, inData *Publish, batchTime time.Time) ([]*Exposure, error) {
 	// Validate the number of keys that want to be published.
 	if len(inData.Keys) == 0 {
 		msg := "no exposure keys in publish request"
@@ -263,6 +351,60 @@ func (t *Transformer) TransformPublish(inData *Publish, batchTime time.Time) ([]
 		return nil, fmt.Errorf(msg)
 	}
 
+	if t.verifier != nil {
+		claims, err := t.verifier.Verify(ctx, inData.VerificationPayload, inData.AppPackageName, inData.Regions, toTEKs(inData.Keys))
+		if err != nil {
+			if t.verifier.Policy() == verification.PolicyFailClosed {
+				return nil, fmt.Errorf("diagnosis verification failed: %w", err)
+			}
+			// PolicyShadow: verification ran but failed and does not block
+			// publication; the failure is logged. There are no claims to apply.
+			log.Printf("diagnosis verification failed (shadow mode, publish not blocked): %v", err)
+		} else if claims != nil {
+			for i, k := range inData.Keys {
+				if tr, ok := claims.TransmissionRiskOverrides[k.Key]; ok {
+					inData.Keys[i].TransmissionRisk = tr
+				}
+			}
+		}
+	}
+
+	if av, ok := t.attestationVerifiers[inData.Platform]; ok {
+		if err := av.VerifyAttestation(ctx, inData.Platform, inData.AppPackageName, inData.DeviceVerificationPayload, inData.AndroidNonce(), inData.IOSNonce()); err != nil {
+			return nil, fmt.Errorf("device attestation failed: %w", err)
+		}
+	}
+
+	if t.externalVerifier != nil {
+		active, err := t.externalVerifier.IsActive(ctx, inData.AppPackageName)
+		if err != nil {
+			return nil, fmt.Errorf("external verification unavailable: %w", err)
+		}
+		if active {
+			decision, err := t.externalVerifier.Verify(ctx, external.VerifyRequest{
+				AppPackageName:      inData.AppPackageName,
+				Regions:             inData.Regions,
+				Keys:                toTEKs(inData.Keys),
+				VerificationPayload: inData.VerificationPayload,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("external verification unavailable: %w", err)
+			}
+			switch decision.Action {
+			case external.ActionReject:
+				return nil, fmt.Errorf("rejected by external verifier: %v", decision.RejectReason)
+			case external.ActionDelay:
+				return nil, fmt.Errorf("publication delayed by external verifier, retry after %v", decision.Delay)
+			}
+			for i, k := range inData.Keys {
+				if tr, ok := decision.TransmissionRiskOverrides[k.Key]; ok {
+					inData.Keys[i].TransmissionRisk = tr
+				}
+			}
+			inData.Regions = append(inData.Regions, decision.AdditionalRegions...)
+		}
+	}
+
 	createdAt := TruncateWindow(batchTime, t.truncateWindow)
 	entities := make([]*Exposure, 0, len(inData.Keys))
 
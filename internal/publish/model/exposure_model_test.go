@@ -0,0 +1,92 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/exposure-notifications-server/internal/verification"
+)
+
+func testPublish(verificationPayload string) *Publish {
+	interval := IntervalNumber(time.Now()) - 1
+	return &Publish{
+		Keys: []ExposureKey{
+			{Key: "AAAAAAAAAAAAAAAAAAAAAA==", IntervalNumber: interval, IntervalCount: 1, TransmissionRisk: 1},
+		},
+		Regions:             []string{"us"},
+		AppPackageName:      "com.example.app",
+		VerificationPayload: verificationPayload,
+	}
+}
+
+func TestTransformPublishVerifierPolicy(t *testing.T) {
+	t.Run("fail closed rejects the publish when the verification payload is invalid", func(t *testing.T) {
+		v := verification.NewVerifier(mustTrustStore(t), nil, verification.PolicyFailClosed)
+		transformer, err := NewTransformer(21, 14*24*time.Hour, 0, WithVerifier(v))
+		if err != nil {
+			t.Fatalf("NewTransformer: %v", err)
+		}
+
+		if _, err := transformer.TransformPublish(context.Background(), testPublish("not-a-jwt"), time.Now()); err == nil {
+			t.Fatal("TransformPublish: want error under PolicyFailClosed with an invalid payload, got nil")
+		}
+	})
+
+	t.Run("shadow does not reject the publish when the verification payload is invalid", func(t *testing.T) {
+		v := verification.NewVerifier(mustTrustStore(t), nil, verification.PolicyShadow)
+		transformer, err := NewTransformer(21, 14*24*time.Hour, 0, WithVerifier(v))
+		if err != nil {
+			t.Fatalf("NewTransformer: %v", err)
+		}
+
+		exposures, err := transformer.TransformPublish(context.Background(), testPublish("not-a-jwt"), time.Now())
+		if err != nil {
+			t.Fatalf("TransformPublish: want no error under PolicyShadow with an invalid payload, got %v", err)
+		}
+		if len(exposures) != 1 {
+			t.Fatalf("TransformPublish: got %v exposures, want 1", len(exposures))
+		}
+	})
+
+	t.Run("no verifier configured skips verification entirely", func(t *testing.T) {
+		transformer, err := NewTransformer(21, 14*24*time.Hour, 0)
+		if err != nil {
+			t.Fatalf("NewTransformer: %v", err)
+		}
+
+		if _, err := transformer.TransformPublish(context.Background(), testPublish(""), time.Now()); err != nil {
+			t.Fatalf("TransformPublish: %v", err)
+		}
+	})
+}
+
+// mustTrustStore returns a TrustStore with one issuer whose DiscoveryURL is
+// unreachable, which is enough to exercise the PolicyFailClosed/PolicyShadow
+// branches of TransformPublish without a real JWKS - the invalid test
+// payload fails parsing before the verifier ever needs to dial it.
+func mustTrustStore(t *testing.T) *verification.TrustStore {
+	t.Helper()
+	store, err := verification.NewTrustStore(&verification.IssuerConfig{
+		Issuer:       "https://issuer.example.health",
+		DiscoveryURL: "https://issuer.example.health/.well-known/openid-configuration",
+	})
+	if err != nil {
+		t.Fatalf("NewTrustStore: %v", err)
+	}
+	return store
+}
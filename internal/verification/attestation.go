@@ -0,0 +1,32 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+)
+
+// AttestationVerifier validates `Publish.DeviceVerificationPayload` against
+// a platform-specific device attestation scheme (Android SafetyNet, iOS App
+// Attest). Implementations are selected by `Publish.Platform` - see
+// `model.WithAttestationVerifiers`.
+//
+// This package intentionally does not depend on internal/publish/model (that
+// package depends on this one to configure a Transformer), so the caller
+// passes the platform-specific nonce it already knows how to compute
+// (`Publish.AndroidNonce`/`Publish.IOSNonce`) rather than the Publish itself.
+type AttestationVerifier interface {
+	VerifyAttestation(ctx context.Context, platform, appPackageName, deviceVerificationPayload, androidNonce, iosNonce string) error
+}
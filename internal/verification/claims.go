@@ -0,0 +1,106 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TEK is the subset of `model.ExposureKey` needed to compute a TEKHash.
+// This package intentionally does not depend on internal/publish/model (that
+// package depends on this one to configure a Verifier), so callers convert
+// their keys to TEK before calling TEKHash/Verify.
+type TEK struct {
+	Key              string
+	IntervalNumber   int32
+	IntervalCount    int32
+	TransmissionRisk int
+}
+
+// Claims is the set of JWT claims this package understands from a
+// health-authority diagnosis verification token.
+//
+// TekMAC / SHA256 are aliases for the same claim; different verification
+// servers have shipped it under either name, so both are accepted and
+// either one satisfies validation.
+// TransmissionRiskOverrides, when present, maps a base64 exposure key to a
+// transmission risk the health authority wants applied in place of the
+// value the client sent, keyed by the same base64 string used in
+// `ExposureKey.Key`.
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Audience  string `json:"aud"`
+	Expiry    int64  `json:"exp"`
+	IssuedAt  int64  `json:"iat"`
+	NotBefore int64  `json:"nbf"`
+
+	TekMAC string `json:"tekmac"`
+	SHA256 string `json:"sha256"`
+
+	TransmissionRiskOverrides map[string]int `json:"trisk"`
+}
+
+// tekClaim returns whichever of tekmac/sha256 is set.
+func (c *Claims) tekClaim() string {
+	if c.TekMAC != "" {
+		return c.TekMAC
+	}
+	return c.SHA256
+}
+
+// validateStandardClaims checks exp/iat/nbf/iss/aud against the given
+// issuer configuration and the current time.
+func (c *Claims) validateStandardClaims(issuer *IssuerConfig, now time.Time) error {
+	if c.Issuer != issuer.Issuer {
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	}
+	if issuer.Audience != "" && c.Audience != issuer.Audience {
+		return fmt.Errorf("unexpected audience %q", c.Audience)
+	}
+	if c.Expiry == 0 || now.Unix() >= c.Expiry {
+		return fmt.Errorf("token is expired")
+	}
+	if c.IssuedAt != 0 && now.Unix() < c.IssuedAt {
+		return fmt.Errorf("token used before issued")
+	}
+	if c.NotBefore != 0 && now.Unix() < c.NotBefore {
+		return fmt.Errorf("token used before nbf")
+	}
+	return nil
+}
+
+// TEKHash computes the canonical hash of the sorted TEK set, in the same
+// form as `model.Publish.AndroidNonce`'s key component, so that it can be
+// compared against the `tekmac`/`sha256` claim in a verification token.
+func TEKHash(keys []TEK) string {
+	sortedKeys := make([]TEK, len(keys))
+	copy(sortedKeys, keys)
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		return sortedKeys[i].Key < sortedKeys[j].Key
+	})
+
+	parts := make([]string, 0, len(sortedKeys))
+	for _, k := range sortedKeys {
+		parts = append(parts, fmt.Sprintf("%v.%v.%v.%v", k.Key, k.IntervalNumber, k.IntervalCount, k.TransmissionRisk))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, ",")))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verification validates the diagnosis verification payload that
+// health authorities attach to a publish request.
+//
+// A verification server (run by or on behalf of a health authority) issues a
+// signed JWT to the mobile client once a diagnosis code has been confirmed.
+// The client passes that JWT back to this server as
+// `Publish.VerificationPayload`. This package validates the JWT the way an
+// OIDC relying party validates an ID token - by fetching the issuer's JWKS,
+// checking standard claims, and confirming that a claim inside the token
+// commits to the exact set of exposure keys being published.
+package verification
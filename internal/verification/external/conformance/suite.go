@@ -0,0 +1,75 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance is a reusable test suite that any ExternalVerifier
+// implementation (in any language) can be checked against from the Go side,
+// by pointing it at a running instance's address. It is not a _test.go file
+// itself: a health authority's own test package imports RunSuite and calls
+// it against their server, e.g.:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.RunSuite(t, "localhost:50051")
+//	}
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/exposure-notifications-server/internal/verification"
+	"github.com/google/exposure-notifications-server/internal/verification/external"
+)
+
+// RunSuite dials addr and checks that the ExternalVerifier it serves
+// behaves per external_verifier.proto: IsActive responds, and Verify
+// returns a terminal decision (ACCEPT/REJECT, or ACCEPT after a DELAY) for
+// a well-formed request within a reasonable deadline.
+func RunSuite(t *testing.T, addr string) {
+	t.Helper()
+
+	pool, err := external.NewPool([]string{addr}, external.WithCallTimeout(10*time.Second))
+	if err != nil {
+		t.Fatalf("conformance: dialing %v: %v", addr, err)
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+
+	t.Run("IsActive responds", func(t *testing.T) {
+		if _, err := pool.IsActive(ctx, "com.example.app"); err != nil {
+			t.Errorf("IsActive: %v", err)
+		}
+	})
+
+	t.Run("Verify returns a decision for a well-formed request", func(t *testing.T) {
+		req := external.VerifyRequest{
+			AppPackageName: "com.example.app",
+			Regions:        []string{"US"},
+			Keys: []verification.TEK{
+				{Key: "AAAAAAAAAAAAAAAAAAAAAA==", IntervalNumber: 100, IntervalCount: 144, TransmissionRisk: 1},
+			},
+		}
+		decision, err := pool.Verify(ctx, req)
+		if err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		if decision == nil {
+			t.Fatal("Verify returned a nil decision")
+		}
+		if decision.Action == external.ActionDelay {
+			t.Error("Verify: final decision is DELAY, want a terminal ACCEPT or REJECT")
+		}
+	})
+}
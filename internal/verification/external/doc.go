@@ -0,0 +1,27 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package external implements a pluggable, out-of-process verification
+// extension point: a gRPC service (external_verifier.proto) that a health
+// authority can run separately from this server to apply
+// verification/enrichment logic without forking it, analogous to KEDA's
+// external scaler pattern.
+//
+// Generated stubs: externalpb.ExternalVerifierClient/Server are produced
+// from external_verifier.proto via:
+//
+//	protoc --go_out=. --go-grpc_out=. external_verifier.proto
+//
+// and checked in under ./externalpb; they are not hand-edited.
+package external
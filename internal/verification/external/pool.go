@@ -0,0 +1,243 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/google/exposure-notifications-server/internal/verification/external/externalpb"
+)
+
+const defaultCallTimeout = 5 * time.Second
+
+// FallbackPolicy controls what Pool.Verify returns when every configured
+// address is unreachable.
+type FallbackPolicy int
+
+const (
+	// FallbackFailClosed rejects the publish request if no external
+	// verifier could be reached.
+	FallbackFailClosed FallbackPolicy = iota
+	// FallbackFailOpen accepts the publish request (with no overrides) if
+	// no external verifier could be reached.
+	FallbackFailOpen
+)
+
+// PoolOption configures a Pool.
+type PoolOption func(*Pool)
+
+// WithCABundle configures the CA bundle (PEM-encoded) used to verify
+// external verifier server certificates, and enables mTLS using clientCert.
+func WithCABundle(caBundle []byte, clientCert tls.Certificate) PoolOption {
+	return func(p *Pool) {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(caBundle)
+		p.tlsConfig = &tls.Config{
+			RootCAs:      pool,
+			Certificates: []tls.Certificate{clientCert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+}
+
+// WithCallTimeout overrides the per-RPC deadline applied to every call made
+// through the pool. Default is defaultCallTimeout.
+func WithCallTimeout(d time.Duration) PoolOption {
+	return func(p *Pool) { p.callTimeout = d }
+}
+
+// WithFallbackPolicy overrides the pool's behavior when no address is
+// reachable. Default is FallbackFailClosed.
+func WithFallbackPolicy(policy FallbackPolicy) PoolOption {
+	return func(p *Pool) { p.fallback = policy }
+}
+
+// Pool is a gRPC-backed Verifier that load-balances across a fixed set of
+// external verifier addresses, pooling one connection per address.
+type Pool struct {
+	conns       map[string]*grpc.ClientConn
+	clients     []externalpb.ExternalVerifierClient
+	tlsConfig   *tls.Config
+	callTimeout time.Duration
+	fallback    FallbackPolicy
+}
+
+// NewPool dials a pooled connection to each address and returns a Pool
+// implementing Verifier. Connections are established lazily by gRPC and
+// retried internally; NewPool does not block on connectivity.
+func NewPool(addrs []string, opts ...PoolOption) (*Pool, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("external: at least one address is required")
+	}
+
+	p := &Pool{
+		conns:       make(map[string]*grpc.ClientConn, len(addrs)),
+		callTimeout: defaultCallTimeout,
+		fallback:    FallbackFailClosed,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	creds := grpc.WithInsecure()
+	if p.tlsConfig != nil {
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(p.tlsConfig))
+	}
+
+	for _, addr := range addrs {
+		conn, err := grpc.Dial(addr, creds)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("external: dialing %v: %w", addr, err)
+		}
+		p.conns[addr] = conn
+		p.clients = append(p.clients, externalpb.NewExternalVerifierClient(conn))
+	}
+	return p, nil
+}
+
+// Close tears down every pooled connection.
+func (p *Pool) Close() error {
+	var firstErr error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// IsActive implements Verifier. It returns true if any pooled verifier is
+// active for appPackageName. If no verifier is reachable, the result honors
+// p.fallback the same way Verify does: FallbackFailOpen reports active (so
+// the caller proceeds straight to Verify, which itself fails open), while
+// FallbackFailClosed returns an error.
+func (p *Pool) IsActive(ctx context.Context, appPackageName string) (bool, error) {
+	reached := false
+	for _, c := range p.clients {
+		callCtx, cancel := context.WithTimeout(ctx, p.callTimeout)
+		resp, err := c.IsActive(callCtx, &externalpb.IsActiveRequest{AppPackageName: appPackageName})
+		cancel()
+		if err != nil {
+			continue
+		}
+		reached = true
+		if resp.Active {
+			return true, nil
+		}
+	}
+	if !reached {
+		if p.fallback == FallbackFailOpen {
+			return true, nil
+		}
+		return false, fmt.Errorf("external: no verifier reachable")
+	}
+	return false, nil
+}
+
+// Verify implements Verifier, sending req to every pooled verifier and
+// combining their decisions: any REJECT wins, else any DELAY wins (using
+// the longest requested delay), else overrides/regions are merged from all
+// ACCEPT decisions.
+func (p *Pool) Verify(ctx context.Context, req VerifyRequest) (*Decision, error) {
+	pbReq := toProto(req)
+
+	merged := &Decision{
+		Action:                    ActionAccept,
+		TransmissionRiskOverrides: map[string]int{},
+	}
+	reached := false
+
+	for _, c := range p.clients {
+		callCtx, cancel := context.WithTimeout(ctx, p.callTimeout)
+		stream, err := c.Verify(callCtx, pbReq)
+		if err != nil {
+			cancel()
+			continue
+		}
+
+		var last *externalpb.VerifyDecision
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				break
+			}
+			last = msg
+		}
+		cancel()
+		if last == nil {
+			continue
+		}
+		reached = true
+		mergeDecision(merged, last)
+	}
+
+	if !reached {
+		if p.fallback == FallbackFailOpen {
+			return &Decision{Action: ActionAccept}, nil
+		}
+		return nil, fmt.Errorf("external: no verifier reachable")
+	}
+	return merged, nil
+}
+
+func mergeDecision(into *Decision, d *externalpb.VerifyDecision) {
+	switch d.Action {
+	case externalpb.Action_REJECT:
+		into.Action = ActionReject
+		into.RejectReason = d.RejectReason
+		return
+	case externalpb.Action_DELAY:
+		if into.Action != ActionReject {
+			into.Action = ActionDelay
+			if delay := time.Duration(d.DelaySeconds) * time.Second; delay > into.Delay {
+				into.Delay = delay
+			}
+		}
+	}
+	for _, o := range d.TransmissionRiskOverrides {
+		into.TransmissionRiskOverrides[o.Key] = int(o.TransmissionRisk)
+	}
+	into.AdditionalRegions = append(into.AdditionalRegions, d.AdditionalRegions...)
+}
+
+func toProto(req VerifyRequest) *externalpb.PublishRequest {
+	keys := make([]*externalpb.ExposureKey, 0, len(req.Keys))
+	for _, k := range req.Keys {
+		keys = append(keys, &externalpb.ExposureKey{
+			Key:              k.Key,
+			IntervalNumber:   k.IntervalNumber,
+			IntervalCount:    k.IntervalCount,
+			TransmissionRisk: int32(k.TransmissionRisk),
+		})
+	}
+	return &externalpb.PublishRequest{
+		AppPackageName:      req.AppPackageName,
+		Regions:             req.Regions,
+		Keys:                keys,
+		VerificationPayload: req.VerificationPayload,
+	}
+}
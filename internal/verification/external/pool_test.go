@@ -0,0 +1,139 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/exposure-notifications-server/internal/verification"
+	"github.com/google/exposure-notifications-server/internal/verification/external/externalpb"
+)
+
+// With no reachable clients, IsActive and Verify must both honor the
+// configured FallbackPolicy rather than always erroring - this is the bug
+// the FallbackFailOpen case exists to prevent.
+func TestIsActiveAndVerifyHonorFallback(t *testing.T) {
+	t.Run("fail closed errors when nothing is reachable", func(t *testing.T) {
+		p := &Pool{fallback: FallbackFailClosed, callTimeout: time.Second}
+
+		if _, err := p.IsActive(context.Background(), "pkg"); err == nil {
+			t.Error("IsActive: want error under FallbackFailClosed, got nil")
+		}
+		if _, err := p.Verify(context.Background(), VerifyRequest{}); err == nil {
+			t.Error("Verify: want error under FallbackFailClosed, got nil")
+		}
+	})
+
+	t.Run("fail open proceeds when nothing is reachable", func(t *testing.T) {
+		p := &Pool{fallback: FallbackFailOpen, callTimeout: time.Second}
+
+		active, err := p.IsActive(context.Background(), "pkg")
+		if err != nil {
+			t.Fatalf("IsActive: %v", err)
+		}
+		if !active {
+			t.Error("IsActive: want true under FallbackFailOpen so the caller proceeds to Verify, got false")
+		}
+
+		decision, err := p.Verify(context.Background(), VerifyRequest{})
+		if err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		if decision.Action != ActionAccept {
+			t.Errorf("Verify: Action = %v, want ActionAccept", decision.Action)
+		}
+	})
+}
+
+func TestMergeDecision(t *testing.T) {
+	t.Run("reject wins over a prior accept", func(t *testing.T) {
+		merged := &Decision{Action: ActionAccept, TransmissionRiskOverrides: map[string]int{}}
+		mergeDecision(merged, &externalpb.VerifyDecision{Action: externalpb.Action_REJECT, RejectReason: "bad"})
+
+		if merged.Action != ActionReject || merged.RejectReason != "bad" {
+			t.Errorf("merged = %+v, want Action=ActionReject RejectReason=bad", merged)
+		}
+	})
+
+	t.Run("longest delay wins among DELAY responses", func(t *testing.T) {
+		merged := &Decision{Action: ActionAccept, TransmissionRiskOverrides: map[string]int{}}
+		mergeDecision(merged, &externalpb.VerifyDecision{Action: externalpb.Action_DELAY, DelaySeconds: 10})
+		mergeDecision(merged, &externalpb.VerifyDecision{Action: externalpb.Action_DELAY, DelaySeconds: 30})
+
+		if merged.Action != ActionDelay || merged.Delay != 30*time.Second {
+			t.Errorf("merged = %+v, want Action=ActionDelay Delay=30s", merged)
+		}
+	})
+
+	t.Run("a later DELAY does not override an earlier REJECT", func(t *testing.T) {
+		merged := &Decision{Action: ActionAccept, TransmissionRiskOverrides: map[string]int{}}
+		mergeDecision(merged, &externalpb.VerifyDecision{Action: externalpb.Action_REJECT, RejectReason: "bad"})
+		mergeDecision(merged, &externalpb.VerifyDecision{Action: externalpb.Action_DELAY, DelaySeconds: 10})
+
+		if merged.Action != ActionReject {
+			t.Errorf("Action = %v, want ActionReject to stick", merged.Action)
+		}
+	})
+
+	t.Run("overrides and regions accumulate across ACCEPT responses", func(t *testing.T) {
+		merged := &Decision{Action: ActionAccept, TransmissionRiskOverrides: map[string]int{}}
+		mergeDecision(merged, &externalpb.VerifyDecision{
+			Action:            externalpb.Action_ACCEPT,
+			AdditionalRegions: []string{"US"},
+			TransmissionRiskOverrides: []*externalpb.TransmissionRiskOverride{
+				{Key: "key-a", TransmissionRisk: 2},
+			},
+		})
+		mergeDecision(merged, &externalpb.VerifyDecision{
+			Action:            externalpb.Action_ACCEPT,
+			AdditionalRegions: []string{"CA"},
+			TransmissionRiskOverrides: []*externalpb.TransmissionRiskOverride{
+				{Key: "key-b", TransmissionRisk: 4},
+			},
+		})
+
+		if merged.Action != ActionAccept {
+			t.Errorf("Action = %v, want ActionAccept", merged.Action)
+		}
+		if merged.TransmissionRiskOverrides["key-a"] != 2 || merged.TransmissionRiskOverrides["key-b"] != 4 {
+			t.Errorf("TransmissionRiskOverrides = %+v, want key-a:2 key-b:4", merged.TransmissionRiskOverrides)
+		}
+		if len(merged.AdditionalRegions) != 2 {
+			t.Errorf("AdditionalRegions = %v, want 2 entries", merged.AdditionalRegions)
+		}
+	})
+}
+
+func TestToProto(t *testing.T) {
+	req := VerifyRequest{
+		AppPackageName: "pkg",
+		Regions:        []string{"US"},
+		Keys: []verification.TEK{
+			{Key: "k1", IntervalNumber: 1, IntervalCount: 144, TransmissionRisk: 2},
+		},
+		VerificationPayload: "tok",
+	}
+
+	pb := toProto(req)
+
+	if pb.AppPackageName != "pkg" || pb.VerificationPayload != "tok" {
+		t.Errorf("toProto dropped top-level fields: %+v", pb)
+	}
+	if len(pb.Keys) != 1 || pb.Keys[0].Key != "k1" || pb.Keys[0].TransmissionRisk != 2 {
+		t.Errorf("toProto: Keys = %+v, want one key k1/risk 2", pb.Keys)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reference is a minimal, correct implementation of the
+// ExternalVerifier gRPC service, meant as a starting point for a health
+// authority standing up their own verifier - not for production use as-is.
+// It accepts every request and applies no overrides.
+package reference
+
+import (
+	"context"
+
+	"github.com/google/exposure-notifications-server/internal/verification/external/externalpb"
+)
+
+// Server is a pass-through ExternalVerifier: it is active for every app and
+// accepts every publish request unmodified.
+type Server struct {
+	externalpb.UnimplementedExternalVerifierServer
+}
+
+// IsActive always returns true.
+func (s *Server) IsActive(ctx context.Context, req *externalpb.IsActiveRequest) (*externalpb.IsActiveResponse, error) {
+	return &externalpb.IsActiveResponse{Active: true}, nil
+}
+
+// Verify always accepts, sending a single ACCEPT decision and closing the
+// stream.
+func (s *Server) Verify(req *externalpb.PublishRequest, stream externalpb.ExternalVerifier_VerifyServer) error {
+	return stream.Send(&externalpb.VerifyDecision{Action: externalpb.Action_ACCEPT})
+}
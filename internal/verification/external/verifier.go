@@ -0,0 +1,69 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/exposure-notifications-server/internal/verification"
+)
+
+// VerifyRequest is what gets sent to an external verifier's Verify RPC.
+type VerifyRequest struct {
+	AppPackageName      string
+	Regions             []string
+	Keys                []verification.TEK
+	VerificationPayload string
+}
+
+// Action is the external verifier's disposition for a VerifyRequest.
+type Action int
+
+const (
+	ActionAccept Action = iota
+	ActionReject
+	// ActionDelay asks the caller to hold publication and re-evaluate later.
+	ActionDelay
+)
+
+// Decision is the (possibly final, possibly intermediate in the DELAY case)
+// result of calling Verify.
+type Decision struct {
+	Action       Action
+	RejectReason string
+	Delay        time.Duration
+
+	// TransmissionRiskOverrides maps a base64 exposure key to the
+	// transmission risk the external verifier wants applied in its place.
+	TransmissionRiskOverrides map[string]int
+	// AdditionalRegions are regions the external verifier wants attached to
+	// the publish in addition to the ones the client sent.
+	AdditionalRegions []string
+}
+
+// Verifier is the client-side view of an external, out-of-process
+// verification service. `Pool` is the gRPC-backed implementation.
+type Verifier interface {
+	// IsActive reports whether this verifier has any policy for
+	// appPackageName. Transformer skips calling Verify when this is false,
+	// so one verifier deployment can selectively cover a subset of apps.
+	IsActive(ctx context.Context, appPackageName string) (bool, error)
+
+	// Verify returns the verifier's decision for a publish request. For a
+	// streaming RPC response, implementations return the last message
+	// received on the stream (the final decision).
+	Verify(ctx context.Context, req VerifyRequest) (*Decision, error)
+}
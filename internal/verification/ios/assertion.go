@@ -0,0 +1,77 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ios
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// authDataLength is the fixed-size prefix of an App Attest authenticator
+// data blob this package reads: a 32 byte RP ID hash, a 1 byte flags field,
+// and a 4 byte big-endian sign count. App Attest authenticator data has no
+// attested credential data or extensions, so nothing else follows.
+const authDataLength = 32 + 1 + 4
+
+// wirePayload is this server's encoding of an App Attest assertion: the CBOR
+// assertion object Apple's DeviceCheck framework produces (`signature` +
+// `authenticatorData`), plus the `keyId` the client registered the
+// attestation under, since the assertion itself does not carry the key ID.
+type wirePayload struct {
+	KeyID             string `cbor:"keyId"`
+	Signature         []byte `cbor:"signature"`
+	AuthenticatorData []byte `cbor:"authenticatorData"`
+}
+
+// assertion is a decoded App Attest assertion: a signature over
+// authenticatorData||clientDataHash, plus the authenticator data itself.
+type assertion struct {
+	keyID       string
+	signature   []byte
+	rpIDHash    []byte
+	signCount   uint32
+	authDataRaw []byte
+}
+
+// parseAssertion decodes a base64'd wirePayload and splits the
+// authenticator data into its fields.
+func parseAssertion(b64 string) (*assertion, error) {
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("decoding deviceVerificationPayload: %w", err)
+	}
+
+	var w wirePayload
+	if err := cbor.Unmarshal(raw, &w); err != nil {
+		return nil, fmt.Errorf("decoding App Attest assertion: %w", err)
+	}
+	if w.KeyID == "" {
+		return nil, fmt.Errorf("assertion is missing keyId")
+	}
+	if len(w.AuthenticatorData) < authDataLength {
+		return nil, fmt.Errorf("authenticatorData too short: %v bytes, want >= %v", len(w.AuthenticatorData), authDataLength)
+	}
+
+	return &assertion{
+		keyID:       w.KeyID,
+		signature:   w.Signature,
+		rpIDHash:    w.AuthenticatorData[0:32],
+		signCount:   binary.BigEndian.Uint32(w.AuthenticatorData[33:37]),
+		authDataRaw: w.AuthenticatorData,
+	}, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package database stores App Attest key registrations and sign counters in
+// the `ios_attest_keys` table, implementing `ios.KeyStore`.
+package database
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/google/exposure-notifications-server/internal/database"
+	"github.com/google/exposure-notifications-server/internal/verification/ios"
+	"github.com/jackc/pgx/v4"
+)
+
+// AttestKeyDB provides access to the `ios_attest_keys` table.
+type AttestKeyDB struct {
+	db *database.DB
+}
+
+// New creates an AttestKeyDB.
+func New(db *database.DB) *AttestKeyDB {
+	return &AttestKeyDB{db: db}
+}
+
+// Get implements ios.KeyStore.
+func (d *AttestKeyDB) Get(ctx context.Context, keyID string) (*ios.AttestKey, error) {
+	var appBundleID string
+	var derPublicKey []byte
+	var signCount uint32
+
+	row := d.db.Pool.QueryRow(ctx, `
+		SELECT app_bundle_id, public_key, sign_count
+		FROM ios_attest_keys
+		WHERE key_id = $1
+	`, keyID)
+	if err := row.Scan(&appBundleID, &derPublicKey, &signCount); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying ios_attest_keys: %w", err)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(derPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stored public key for %v: %w", keyID, err)
+	}
+	ecPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("stored public key for %v is not an EC key", keyID)
+	}
+
+	return &ios.AttestKey{
+		KeyID:       keyID,
+		AppBundleID: appBundleID,
+		PublicKey:   ecPub,
+		SignCount:   signCount,
+	}, nil
+}
+
+// AdvanceSignCount implements ios.KeyStore.
+func (d *AttestKeyDB) AdvanceSignCount(ctx context.Context, keyID string, newCount uint32) error {
+	tag, err := d.db.Pool.Exec(ctx, `
+		UPDATE ios_attest_keys
+		SET sign_count = $1
+		WHERE key_id = $2 AND sign_count < $1
+	`, newCount, keyID)
+	if err != nil {
+		return fmt.Errorf("updating sign count for %v: %w", keyID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("sign count for %v was not advanced, possible concurrent replay", keyID)
+	}
+	return nil
+}
+
+// Register adds a newly-attested key to the table. Called once, when the
+// client first performs App Attest key attestation (as opposed to the
+// per-publish assertion this package otherwise validates).
+func (d *AttestKeyDB) Register(ctx context.Context, keyID, appBundleID string, pub *ecdsa.PublicKey) error {
+	if pub.Curve != elliptic.P256() {
+		return fmt.Errorf("only P-256 App Attest keys are supported")
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return fmt.Errorf("marshaling public key for %v: %w", keyID, err)
+	}
+
+	_, err = d.db.Pool.Exec(ctx, `
+		INSERT INTO ios_attest_keys (key_id, app_bundle_id, public_key, sign_count)
+		VALUES ($1, $2, $3, 0)
+		ON CONFLICT (key_id) DO NOTHING
+	`, keyID, appBundleID, der)
+	if err != nil {
+		return fmt.Errorf("inserting ios_attest_keys row for %v: %w", keyID, err)
+	}
+	return nil
+}
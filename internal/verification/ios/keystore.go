@@ -0,0 +1,44 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ios
+
+import (
+	"context"
+	"crypto/ecdsa"
+)
+
+// AttestKey is a previously-registered App Attest public key, along with the
+// last signature counter this server observed for it.
+type AttestKey struct {
+	KeyID       string
+	AppBundleID string
+	PublicKey   *ecdsa.PublicKey
+	SignCount   uint32
+}
+
+// KeyStore registers and looks up App Attest keys, and tracks each key's
+// sign counter so that a replayed assertion (one reusing a counter value
+// this server has already seen) is rejected. Implemented by
+// `ios/database.AttestKeyDB` against the `ios_attest_keys` table.
+type KeyStore interface {
+	// Get returns the registered key for keyID, or (nil, nil) if it is not
+	// registered.
+	Get(ctx context.Context, keyID string) (*AttestKey, error)
+
+	// AdvanceSignCount persists newCount as the latest observed sign count
+	// for keyID. Callers must have already checked newCount > the
+	// previously stored value.
+	AdvanceSignCount(ctx context.Context, keyID string, newCount uint32) error
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ios
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// Verifier validates App Attest assertions carried in
+// `Publish.DeviceVerificationPayload` against a KeyStore of previously
+// registered public keys for this app's BundleID. It implements
+// `verification.AttestationVerifier`.
+type Verifier struct {
+	keys     KeyStore
+	teamID   string
+	bundleID string
+}
+
+// NewVerifier creates a Verifier that only accepts assertions whose RP ID
+// hash matches the App ID `teamID + "." + bundleID` - this is what Apple's
+// App Attest authenticator data actually commits to (DevCenter > Identifiers
+// "App ID Prefix" is the team ID), not the bundle ID alone.
+func NewVerifier(keys KeyStore, teamID, bundleID string) *Verifier {
+	return &Verifier{keys: keys, teamID: teamID, bundleID: bundleID}
+}
+
+// VerifyAttestation implements verification.AttestationVerifier. androidNonce
+// is ignored; it is only present so the same interface serves both
+// platforms.
+func (v *Verifier) VerifyAttestation(ctx context.Context, platform, appPackageName, deviceVerificationPayload, androidNonce, iosNonce string) error {
+	a, err := parseAssertion(deviceVerificationPayload)
+	if err != nil {
+		return fmt.Errorf("ios attestation: %w", err)
+	}
+
+	key, err := v.keys.Get(ctx, a.keyID)
+	if err != nil {
+		return fmt.Errorf("ios attestation: looking up key %v: %w", a.keyID, err)
+	}
+	if key == nil {
+		return fmt.Errorf("ios attestation: key %v is not registered", a.keyID)
+	}
+	if key.AppBundleID != v.bundleID {
+		return fmt.Errorf("ios attestation: key %v is registered to a different app", a.keyID)
+	}
+
+	appID := v.teamID + "." + v.bundleID
+	wantRPIDHash := sha256.Sum256([]byte(appID))
+	if !bytes.Equal(a.rpIDHash, wantRPIDHash[:]) {
+		return fmt.Errorf("ios attestation: RP ID hash does not match App ID %v", appID)
+	}
+
+	if a.signCount <= key.SignCount {
+		return fmt.Errorf("ios attestation: sign count %v did not advance past stored count %v (possible replay)", a.signCount, key.SignCount)
+	}
+
+	clientDataHash := sha256.Sum256([]byte(iosNonce))
+	nonce := sha256.Sum256(append(append([]byte{}, a.authDataRaw...), clientDataHash[:]...))
+
+	if err := verifyES256(key.PublicKey, nonce[:], a.signature); err != nil {
+		return fmt.Errorf("ios attestation: %w", err)
+	}
+
+	if err := v.keys.AdvanceSignCount(ctx, a.keyID, a.signCount); err != nil {
+		return fmt.Errorf("ios attestation: recording sign count: %w", err)
+	}
+	return nil
+}
+
+// verifyES256 verifies an ASN.1 DER-encoded ECDSA signature, the format App
+// Attest assertions use (unlike the raw r||s encoding used by JWS ES256).
+func verifyES256(pub *ecdsa.PublicKey, digest, derSig []byte) error {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(derSig, &sig); err != nil {
+		return fmt.Errorf("parsing signature: %w", err)
+	}
+	if !ecdsa.Verify(pub, digest, sig.R, sig.S) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
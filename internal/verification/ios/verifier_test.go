@@ -0,0 +1,168 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ios
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	testTeamID   = "ABCDE12345"
+	testBundleID = "com.example.app"
+	testKeyID    = "key-1"
+)
+
+// fakeKeyStore is an in-memory KeyStore for tests.
+type fakeKeyStore struct {
+	keys map[string]*AttestKey
+}
+
+func (s *fakeKeyStore) Get(ctx context.Context, keyID string) (*AttestKey, error) {
+	return s.keys[keyID], nil
+}
+
+func (s *fakeKeyStore) AdvanceSignCount(ctx context.Context, keyID string, newCount uint32) error {
+	s.keys[keyID].SignCount = newCount
+	return nil
+}
+
+// signAssertion builds a wirePayload for authDataRaw||clientDataHash signed
+// by priv, matching the ASN.1 DER encoding App Attest assertions use.
+func signAssertion(t *testing.T, priv *ecdsa.PrivateKey, authDataRaw, clientDataHash []byte) string {
+	t.Helper()
+
+	digest := sha256.Sum256(append(append([]byte{}, authDataRaw...), clientDataHash...))
+	r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.Sign: %v", err)
+	}
+	derSig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatalf("asn1.Marshal: %v", err)
+	}
+
+	raw, err := cbor.Marshal(wirePayload{
+		KeyID:             testKeyID,
+		Signature:         derSig,
+		AuthenticatorData: authDataRaw,
+	})
+	if err != nil {
+		t.Fatalf("cbor.Marshal: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func authData(rpIDHash [32]byte, signCount uint32) []byte {
+	data := make([]byte, authDataLength)
+	copy(data[0:32], rpIDHash[:])
+	binary.BigEndian.PutUint32(data[33:37], signCount)
+	return data
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	appID := testTeamID + "." + testBundleID
+	rpIDHash := sha256.Sum256([]byte(appID))
+	iosNonce := "canonical-cleartext"
+	clientDataHash := sha256.Sum256([]byte(iosNonce))
+
+	t.Run("valid assertion is accepted and advances sign count", func(t *testing.T) {
+		store := &fakeKeyStore{keys: map[string]*AttestKey{
+			testKeyID: {KeyID: testKeyID, AppBundleID: testBundleID, PublicKey: &priv.PublicKey, SignCount: 5},
+		}}
+		v := NewVerifier(store, testTeamID, testBundleID)
+
+		authDataRaw := authData(rpIDHash, 6)
+		payload := signAssertion(t, priv, authDataRaw, clientDataHash[:])
+
+		if err := v.VerifyAttestation(context.Background(), "ios", "pkg", payload, "", iosNonce); err != nil {
+			t.Fatalf("VerifyAttestation: %v", err)
+		}
+		if got := store.keys[testKeyID].SignCount; got != 6 {
+			t.Errorf("SignCount = %v, want 6", got)
+		}
+	})
+
+	t.Run("replayed sign count is rejected", func(t *testing.T) {
+		store := &fakeKeyStore{keys: map[string]*AttestKey{
+			testKeyID: {KeyID: testKeyID, AppBundleID: testBundleID, PublicKey: &priv.PublicKey, SignCount: 6},
+		}}
+		v := NewVerifier(store, testTeamID, testBundleID)
+
+		authDataRaw := authData(rpIDHash, 6)
+		payload := signAssertion(t, priv, authDataRaw, clientDataHash[:])
+
+		if err := v.VerifyAttestation(context.Background(), "ios", "pkg", payload, "", iosNonce); err == nil {
+			t.Fatal("VerifyAttestation: want error for non-advancing sign count, got nil")
+		}
+	})
+
+	t.Run("wrong app ID hash is rejected", func(t *testing.T) {
+		store := &fakeKeyStore{keys: map[string]*AttestKey{
+			testKeyID: {KeyID: testKeyID, AppBundleID: testBundleID, PublicKey: &priv.PublicKey, SignCount: 5},
+		}}
+		v := NewVerifier(store, testTeamID, testBundleID)
+
+		wrongHash := sha256.Sum256([]byte("some.other.app"))
+		authDataRaw := authData(wrongHash, 6)
+		payload := signAssertion(t, priv, authDataRaw, clientDataHash[:])
+
+		if err := v.VerifyAttestation(context.Background(), "ios", "pkg", payload, "", iosNonce); err == nil {
+			t.Fatal("VerifyAttestation: want error for mismatched RP ID hash, got nil")
+		}
+	})
+
+	t.Run("tampered signature is rejected", func(t *testing.T) {
+		store := &fakeKeyStore{keys: map[string]*AttestKey{
+			testKeyID: {KeyID: testKeyID, AppBundleID: testBundleID, PublicKey: &priv.PublicKey, SignCount: 5},
+		}}
+		v := NewVerifier(store, testTeamID, testBundleID)
+
+		authDataRaw := authData(rpIDHash, 6)
+		// Sign over a different nonce than the one passed to VerifyAttestation.
+		otherClientDataHash := sha256.Sum256([]byte("different-nonce"))
+		payload := signAssertion(t, priv, authDataRaw, otherClientDataHash[:])
+
+		if err := v.VerifyAttestation(context.Background(), "ios", "pkg", payload, "", iosNonce); err == nil {
+			t.Fatal("VerifyAttestation: want error for signature over wrong clientDataHash, got nil")
+		}
+	})
+
+	t.Run("unregistered key is rejected", func(t *testing.T) {
+		store := &fakeKeyStore{keys: map[string]*AttestKey{}}
+		v := NewVerifier(store, testTeamID, testBundleID)
+
+		authDataRaw := authData(rpIDHash, 6)
+		payload := signAssertion(t, priv, authDataRaw, clientDataHash[:])
+
+		if err := v.VerifyAttestation(context.Background(), "ios", "pkg", payload, "", iosNonce); err == nil {
+			t.Fatal("VerifyAttestation: want error for unregistered key, got nil")
+		}
+	})
+}
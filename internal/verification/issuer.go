@@ -0,0 +1,111 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssuerConfig describes a single health-authority verification server that
+// this deployment is willing to trust.
+//
+// Issuer: the `iss` claim expected in tokens from this authority.
+// DiscoveryURL: the OIDC well-known discovery document, e.g.
+//   https://verification.example.health/.well-known/openid-configuration
+//   The JWKS URI is resolved from this document and cached in memory.
+// Audience: the `aud` claim this server expects tokens to carry.
+// AppPackageNames: the set of AppPackageName values this issuer is allowed
+//   to vouch for. Empty means "any".
+// Regions: the set of regions this issuer is allowed to vouch for. Empty
+//   means "any".
+type IssuerConfig struct {
+	Issuer          string
+	DiscoveryURL    string
+	Audience        string
+	AppPackageNames []string
+	Regions         []string
+}
+
+func (c *IssuerConfig) allowsAppPackageName(name string) bool {
+	if len(c.AppPackageNames) == 0 {
+		return true
+	}
+	for _, n := range c.AppPackageNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *IssuerConfig) allowsRegions(regions []string) bool {
+	if len(c.Regions) == 0 {
+		return true
+	}
+	allowed := make(map[string]bool, len(c.Regions))
+	for _, r := range c.Regions {
+		allowed[strings.ToUpper(r)] = true
+	}
+	for _, r := range regions {
+		if !allowed[strings.ToUpper(r)] {
+			return false
+		}
+	}
+	return true
+}
+
+// TrustStore is the set of IssuerConfig entries this server trusts, indexed
+// for lookup by AppPackageName/region so that a request can be routed to the
+// correct health authority's verification server.
+type TrustStore struct {
+	issuers []*IssuerConfig
+}
+
+// NewTrustStore builds a TrustStore from the given issuer configurations.
+func NewTrustStore(issuers ...*IssuerConfig) (*TrustStore, error) {
+	for i, c := range issuers {
+		if c.Issuer == "" {
+			return nil, fmt.Errorf("issuer configuration %v is missing Issuer", i)
+		}
+		if c.DiscoveryURL == "" {
+			return nil, fmt.Errorf("issuer configuration %v (%v) is missing DiscoveryURL", i, c.Issuer)
+		}
+	}
+	return &TrustStore{issuers: issuers}, nil
+}
+
+// ForRequest returns the IssuerConfig entries that are allowed to vouch for
+// the given AppPackageName and regions.
+func (t *TrustStore) ForRequest(appPackageName string, regions []string) []*IssuerConfig {
+	matches := make([]*IssuerConfig, 0, 1)
+	for _, c := range t.issuers {
+		if c.allowsAppPackageName(appPackageName) && c.allowsRegions(regions) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// ByIssuer returns the IssuerConfig registered for the given `iss` claim, or
+// nil if that issuer isn't trusted.
+func (t *TrustStore) ByIssuer(issuer string) *IssuerConfig {
+	for _, c := range t.issuers {
+		if c.Issuer == issuer {
+			return c
+		}
+	}
+	return nil
+}
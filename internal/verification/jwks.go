@@ -0,0 +1,167 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultJWKSRefresh is the default maximum age of a cached JWKS; a lookup
+// against an older entry refetches it.
+const defaultJWKSRefresh = 15 * time.Minute
+
+// jwk is a single entry of a JSON Web Key Set, limited to the fields this
+// package understands (EC and RSA public keys).
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type discoveryDocument struct {
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type cachedKeySet struct {
+	fetchedAt time.Time
+	jwksURI   string
+	keys      map[string]crypto.PublicKey
+}
+
+// JWKSCache fetches and caches the JSON Web Key Set published by each
+// issuer's OIDC discovery document, keyed by `kid`. Refetching happens
+// synchronously inside Key, on the lookup that finds the cached entry stale
+// or missing the requested `kid`; nothing is refreshed in the background.
+type JWKSCache struct {
+	httpClient *http.Client
+	refresh    time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]*cachedKeySet // keyed by DiscoveryURL
+}
+
+// NewJWKSCache creates a JWKSCache whose entries are refetched once they are
+// older than `refresh`. A zero refresh uses defaultJWKSRefresh.
+func NewJWKSCache(httpClient *http.Client, refresh time.Duration) *JWKSCache {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if refresh <= 0 {
+		refresh = defaultJWKSRefresh
+	}
+	return &JWKSCache{
+		httpClient: httpClient,
+		refresh:    refresh,
+		cache:      make(map[string]*cachedKeySet),
+	}
+}
+
+// Key returns the public key for the given `kid`, fetching and caching the
+// issuer's JWKS (via its discovery document) if needed.
+func (c *JWKSCache) Key(ctx context.Context, discoveryURL, kid string) (crypto.PublicKey, error) {
+	if entry := c.lookup(discoveryURL); entry != nil {
+		if key, ok := entry.keys[kid]; ok {
+			return key, nil
+		}
+		// kid not found in a fresh-enough cache: the issuer may have rotated
+		// keys, fall through and force a refetch.
+	}
+
+	entry, err := c.fetch(ctx, discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[discoveryURL] = entry
+	c.mu.Unlock()
+
+	key, ok := entry.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("verification: no key with kid %q published by %v", kid, discoveryURL)
+	}
+	return key, nil
+}
+
+func (c *JWKSCache) lookup(discoveryURL string) *cachedKeySet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.cache[discoveryURL]
+	if !ok || time.Since(entry.fetchedAt) > c.refresh {
+		return nil
+	}
+	return entry
+}
+
+func (c *JWKSCache) fetch(ctx context.Context, discoveryURL string) (*cachedKeySet, error) {
+	var doc discoveryDocument
+	if err := getJSON(ctx, c.httpClient, discoveryURL, &doc); err != nil {
+		return nil, fmt.Errorf("verification: fetching discovery document %v: %w", discoveryURL, err)
+	}
+	if doc.JWKSURI == "" {
+		return nil, fmt.Errorf("verification: discovery document %v has no jwks_uri", discoveryURL)
+	}
+
+	var set jwkSet
+	if err := getJSON(ctx, c.httpClient, doc.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("verification: fetching jwks %v: %w", doc.JWKSURI, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			continue // skip key types we don't understand, don't fail the whole set
+		}
+		keys[k.Kid] = pub
+	}
+
+	return &cachedKeySet{
+		fetchedAt: time.Now(),
+		jwksURI:   doc.JWKSURI,
+		keys:      keys,
+	}, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %v", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,191 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Policy controls what a Verifier does with the outcome of verification.
+type Policy int
+
+const (
+	// PolicyFailClosed rejects the publish request if the verification
+	// payload is missing, malformed, or fails any check.
+	PolicyFailClosed Policy = iota
+	// PolicyShadow runs verification but never rejects the publish request;
+	// model.Transformer logs a failed verification instead. Useful for
+	// onboarding a health authority without risking client-visible failures.
+	PolicyShadow
+)
+
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verifier validates `Publish.VerificationPayload` against a TrustStore of
+// health-authority issuers.
+type Verifier struct {
+	trustStore *TrustStore
+	jwks       *JWKSCache
+	policy     Policy
+}
+
+// NewVerifier creates a Verifier for the given trust store. `jwks` may be
+// nil, in which case a JWKSCache with default settings is created.
+func NewVerifier(trustStore *TrustStore, jwks *JWKSCache, policy Policy) *Verifier {
+	if jwks == nil {
+		jwks = NewJWKSCache(nil, 0)
+	}
+	return &Verifier{
+		trustStore: trustStore,
+		jwks:       jwks,
+		policy:     policy,
+	}
+}
+
+// Policy returns the Verifier's configured policy.
+func (v *Verifier) Policy() Policy {
+	return v.policy
+}
+
+// Verify parses and validates `payload` as a diagnosis verification JWT for
+// the given publish request, returning the validated claims. The caller
+// (typically `model.Transformer`) decides how to react to an error based on
+// the configured Policy.
+func (v *Verifier) Verify(ctx context.Context, payload string, appPackageName string, regions []string, keys []TEK) (*Claims, error) {
+	if payload == "" {
+		return nil, fmt.Errorf("verification: empty verification payload")
+	}
+
+	header, claims, signedData, sig, err := parseJWT(payload)
+	if err != nil {
+		return nil, fmt.Errorf("verification: %w", err)
+	}
+
+	if !AllowedAlgs[header.Alg] {
+		return nil, fmt.Errorf("verification: alg %q is not allowed", header.Alg)
+	}
+
+	issuer := v.trustStore.ByIssuer(claims.Issuer)
+	if issuer == nil {
+		return nil, fmt.Errorf("verification: issuer %q is not trusted", claims.Issuer)
+	}
+	if !issuer.allowsAppPackageName(appPackageName) {
+		return nil, fmt.Errorf("verification: issuer %q may not vouch for app %q", claims.Issuer, appPackageName)
+	}
+	if !issuer.allowsRegions(regions) {
+		return nil, fmt.Errorf("verification: issuer %q may not vouch for regions %v", claims.Issuer, regions)
+	}
+
+	key, err := v.jwks.Key(ctx, issuer.DiscoveryURL, header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("verification: %w", err)
+	}
+	if err := verifySignature(header.Alg, key, signedData, sig); err != nil {
+		return nil, fmt.Errorf("verification: invalid signature: %w", err)
+	}
+
+	if err := claims.validateStandardClaims(issuer, time.Now()); err != nil {
+		return nil, fmt.Errorf("verification: %w", err)
+	}
+
+	want := TEKHash(keys)
+	got := claims.tekClaim()
+	if got == "" {
+		return nil, fmt.Errorf("verification: token has no tekmac/sha256 claim")
+	}
+	if got != want {
+		return nil, fmt.Errorf("verification: tek hash does not match claim")
+	}
+
+	return claims, nil
+}
+
+// parseJWT splits and decodes a compact JWS, returning the header, the
+// unmarshaled claims, the exact bytes that were signed (header.payload),
+// and the decoded signature.
+func parseJWT(token string) (*jwsHeader, *Claims, []byte, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, nil, fmt.Errorf("malformed token, expected 3 segments, got %v", len(parts))
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("decoding header: %w", err)
+	}
+	var header jwsHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("parsing header: %w", err)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("decoding claims: %w", err)
+	}
+	var claims Claims
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("parsing claims: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("decoding signature: %w", err)
+	}
+
+	signedData := []byte(parts[0] + "." + parts[1])
+	return &header, &claims, signedData, sig, nil
+}
+
+func verifySignature(alg string, key interface{}, signedData, sig []byte) error {
+	switch alg {
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key is not an EC public key")
+		}
+		if len(sig) != 64 {
+			return fmt.Errorf("invalid ES256 signature length %v", len(sig))
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		sum := sha256.Sum256(signedData)
+		if !ecdsa.Verify(pub, sum[:], r, s) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key is not an RSA public key")
+		}
+		sum := sha256.Sum256(signedData)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig)
+	default:
+		return fmt.Errorf("unsupported alg %q", alg)
+	}
+}
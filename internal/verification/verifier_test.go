@@ -0,0 +1,240 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://issuer.example.health"
+	testKid      = "key-1"
+	testAppPkg   = "com.example.app"
+	testKeyValue = "AAAAAAAAAAAAAAAAAAAAAA=="
+)
+
+// newTestVerificationServer serves a discovery document and JWKS containing
+// exactly the one given key, so tests can exercise JWKSCache the same way a
+// real health authority's verification server would be hit.
+func newTestVerificationServer(t *testing.T, j jwk) (*httptest.Server, func()) {
+	t.Helper()
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discoveryDocument{JWKSURI: srv.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jwkSet{Keys: []jwk{j}})
+	})
+	srv = httptest.NewServer(mux)
+	return srv, srv.Close
+}
+
+func ecJWK(pub *ecdsa.PublicKey, kid string) jwk {
+	return jwk{
+		Kid: kid,
+		Kty: "EC",
+		Alg: "ES256",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(pub.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(pub.Y.Bytes()),
+	}
+}
+
+func rsaJWK(pub *rsa.PublicKey, kid string) jwk {
+	e := big.NewInt(int64(pub.E)).Bytes()
+	return jwk{
+		Kid: kid,
+		Kty: "RSA",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(e),
+	}
+}
+
+// mintToken builds a compact JWS for claims, signed with priv under alg/kid.
+// priv must be a *ecdsa.PrivateKey for ES256 or a *rsa.PrivateKey for RS256.
+func mintToken(t *testing.T, alg, kid string, claims Claims, priv interface{}) string {
+	t.Helper()
+
+	header, err := json.Marshal(jwsHeader{Alg: alg, Kid: kid})
+	if err != nil {
+		t.Fatalf("marshaling header: %v", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshaling claims: %v", err)
+	}
+
+	signedData := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	sum := sha256.Sum256([]byte(signedData))
+
+	var sig []byte
+	switch alg {
+	case "ES256":
+		key := priv.(*ecdsa.PrivateKey)
+		r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+		if err != nil {
+			t.Fatalf("ecdsa.Sign: %v", err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	case "RS256":
+		key := priv.(*rsa.PrivateKey)
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+		if err != nil {
+			t.Fatalf("rsa.SignPKCS1v15: %v", err)
+		}
+	default:
+		t.Fatalf("mintToken: unsupported alg %q", alg)
+	}
+
+	return signedData + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func baseClaims(now time.Time, keys []TEK) Claims {
+	return Claims{
+		Issuer:   testIssuer,
+		Expiry:   now.Add(time.Hour).Unix(),
+		IssuedAt: now.Unix(),
+		TekMAC:   TEKHash(keys),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	keys := []TEK{{Key: testKeyValue, IntervalNumber: 100, IntervalCount: 144, TransmissionRisk: 1}}
+	now := time.Now()
+
+	ecPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+	rsaPriv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+
+	newVerifier := func(t *testing.T, j jwk) (*Verifier, func()) {
+		t.Helper()
+		srv, closeSrv := newTestVerificationServer(t, j)
+		issuer := &IssuerConfig{Issuer: testIssuer, DiscoveryURL: srv.URL + "/.well-known/openid-configuration"}
+		store, err := NewTrustStore(issuer)
+		if err != nil {
+			t.Fatalf("NewTrustStore: %v", err)
+		}
+		return NewVerifier(store, nil, PolicyFailClosed), closeSrv
+	}
+
+	t.Run("validly signed ES256 token is accepted", func(t *testing.T) {
+		v, closeSrv := newVerifier(t, ecJWK(&ecPriv.PublicKey, testKid))
+		defer closeSrv()
+
+		token := mintToken(t, "ES256", testKid, baseClaims(now, keys), ecPriv)
+		if _, err := v.Verify(context.Background(), token, testAppPkg, nil, keys); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+	})
+
+	t.Run("validly signed RS256 token is accepted", func(t *testing.T) {
+		v, closeSrv := newVerifier(t, rsaJWK(&rsaPriv.PublicKey, testKid))
+		defer closeSrv()
+
+		token := mintToken(t, "RS256", testKid, baseClaims(now, keys), rsaPriv)
+		if _, err := v.Verify(context.Background(), token, testAppPkg, nil, keys); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+	})
+
+	t.Run("wrong kid is rejected", func(t *testing.T) {
+		v, closeSrv := newVerifier(t, ecJWK(&ecPriv.PublicKey, testKid))
+		defer closeSrv()
+
+		token := mintToken(t, "ES256", "some-other-kid", baseClaims(now, keys), ecPriv)
+		if _, err := v.Verify(context.Background(), token, testAppPkg, nil, keys); err == nil {
+			t.Fatal("Verify: want error for unknown kid, got nil")
+		}
+	})
+
+	t.Run("disallowed alg is rejected", func(t *testing.T) {
+		v, closeSrv := newVerifier(t, ecJWK(&ecPriv.PublicKey, testKid))
+		defer closeSrv()
+
+		token := mintToken(t, "ES256", testKid, baseClaims(now, keys), ecPriv)
+		parts := strings.Split(token, ".")
+		noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"` + testKid + `"}`))
+		token = noneHeader + "." + parts[1] + "." + parts[2]
+
+		if _, err := v.Verify(context.Background(), token, testAppPkg, nil, keys); err == nil {
+			t.Fatal("Verify: want error for disallowed alg \"none\", got nil")
+		}
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		v, closeSrv := newVerifier(t, ecJWK(&ecPriv.PublicKey, testKid))
+		defer closeSrv()
+
+		claims := baseClaims(now, keys)
+		claims.Expiry = now.Add(-time.Minute).Unix()
+		token := mintToken(t, "ES256", testKid, claims, ecPriv)
+		if _, err := v.Verify(context.Background(), token, testAppPkg, nil, keys); err == nil {
+			t.Fatal("Verify: want error for expired token, got nil")
+		}
+	})
+
+	t.Run("not-yet-valid token is rejected", func(t *testing.T) {
+		v, closeSrv := newVerifier(t, ecJWK(&ecPriv.PublicKey, testKid))
+		defer closeSrv()
+
+		claims := baseClaims(now, keys)
+		claims.NotBefore = now.Add(time.Hour).Unix()
+		token := mintToken(t, "ES256", testKid, claims, ecPriv)
+		if _, err := v.Verify(context.Background(), token, testAppPkg, nil, keys); err == nil {
+			t.Fatal("Verify: want error for not-yet-valid token, got nil")
+		}
+	})
+
+	t.Run("tampered signature is rejected", func(t *testing.T) {
+		v, closeSrv := newVerifier(t, ecJWK(&ecPriv.PublicKey, testKid))
+		defer closeSrv()
+
+		token := mintToken(t, "ES256", testKid, baseClaims(now, keys), ecPriv)
+		parts := strings.Split(token, ".")
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatalf("decoding signature: %v", err)
+		}
+		sig[0] ^= 0xFF
+		tampered := parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(sig)
+
+		if _, err := v.Verify(context.Background(), tampered, testAppPkg, nil, keys); err == nil {
+			t.Fatal("Verify: want error for tampered signature, got nil")
+		}
+	})
+}
@@ -42,6 +42,9 @@ var (
 	signingKeyVersion = flag.String("signing-key-version", "", "The version of the signing key (for clients).")
 	appPkgID          = flag.String("app-pkg-id", "", "The App Package ID to put in export headers")
 	bundleID          = flag.String("bundle-id", "", "The BundleID to put in export headers")
+	shardCount        = flag.Int("shard-count", 1, "The number of workers that generate this export's batches in parallel.")
+	cursorPageSize    = flag.Int("cursor-page-size", 1000, "The number of exposure rows fetched per keyset-paginated page.")
+	dedupEnabled      = flag.Bool("dedup", false, "Reuse an existing object instead of uploading a duplicate when a batch's content is identical to one already exported.")
 )
 
 func main() {
@@ -110,6 +113,9 @@ func main() {
 		From:             fromTime,
 		Thru:             thruTime,
 		SignatureInfoIDs: []int64{si.ID},
+		ShardCount:       *shardCount,
+		CursorPageSize:   *cursorPageSize,
+		DedupEnabled:     *dedupEnabled,
 	}
 	if err := database.New(db).AddExportConfig(ctx, &ec); err != nil {
 		log.Fatalf("Failure: %v", err)